@@ -0,0 +1,327 @@
+// Package dice parses and evaluates TRPG dice notation.
+//
+// Supported notation (case-insensitive, full-width characters accepted):
+//
+//	3D6, D6           N dice with M sides (N defaults to 1)
+//	1D100, 1D%        percentile dice
+//	4D6KH3, 4D6K3     keep highest 3 (KL = keep lowest)
+//	4D6DL1            drop lowest 1 (DH = drop highest)
+//	2D6+6, (1D6+1)*2  arithmetic with + - * / and parentheses (/ rounds down)
+//	1D100<=65         comparison against a target (<=, <, >=, >, =)
+package dice
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Limits guard against notation that would allocate huge roll lists.
+const (
+	MaxDice  = 1000
+	MaxSides = 10000
+)
+
+// Source produces uniformly distributed integers in [0, n).
+// *rand.Rand satisfies it.
+type Source interface {
+	IntN(n int) int
+}
+
+// Roller rolls dice using a Source. It is safe for concurrent use.
+type Roller struct {
+	mu  sync.Mutex
+	src Source
+}
+
+// NewRoller creates a Roller backed by src.
+func NewRoller(src Source) *Roller {
+	return &Roller{src: src}
+}
+
+// Seeded creates a deterministic Roller for tests and replays.
+func Seeded(seed uint64) *Roller {
+	return NewRoller(rand.New(rand.NewPCG(seed, seed)))
+}
+
+// defaultRoller uses the randomly seeded global generator.
+var defaultRoller = NewRoller(globalSource{})
+
+type globalSource struct{}
+
+func (globalSource) IntN(n int) int { return rand.IntN(n) }
+
+// Default returns the package-level Roller.
+func Default() *Roller {
+	return defaultRoller
+}
+
+// Roll parses and rolls notation with the default Roller.
+func Roll(notation string) (Result, error) {
+	return defaultRoller.Roll(notation)
+}
+
+// Roll parses and rolls notation.
+func (r *Roller) Roll(notation string) (Result, error) {
+	e, err := Parse(notation)
+	if err != nil {
+		return Result{}, err
+	}
+	return e.Roll(r)
+}
+
+// die rolls a single die with the given number of sides.
+func (r *Roller) die(sides int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.src.IntN(sides) + 1
+}
+
+// Die is a single rolled die.
+type Die struct {
+	Value   int
+	Dropped bool // excluded by a keep/drop modifier
+}
+
+// DiceRoll is the outcome of one NdM term.
+type DiceRoll struct {
+	Notation string // e.g. "4D6DL1"
+	Sides    int
+	Dice     []Die
+	Total    int // sum of kept dice
+}
+
+// Result is the outcome of rolling an expression.
+type Result struct {
+	Notation string     // normalized notation, e.g. "1D100<=65"
+	Detail   string     // expression with rolled dice, e.g. "3D6[4,2,6]+3"
+	Total    int        // value of the left-hand expression
+	Rolls    []DiceRoll // every dice term in evaluation order
+
+	HasTarget bool   // true when the notation had a comparison
+	Op        string // comparison operator
+	Target    int    // value of the right-hand expression
+	Success   bool   // comparison outcome
+}
+
+// Breakdown returns a human-readable summary, e.g. "3D6[4,2,6]+3 = 15" or "1D100[42] = 42 <= 65".
+func (r Result) Breakdown() string {
+	s := r.Detail + " = " + strconv.Itoa(r.Total)
+	if r.HasTarget {
+		s += " " + r.Op + " " + strconv.Itoa(r.Target)
+	}
+	return s
+}
+
+// Expr is a parsed dice expression.
+type Expr struct {
+	notation string
+	left     node
+	op       string
+	right    node
+}
+
+// String returns the normalized notation.
+func (e *Expr) String() string {
+	return e.notation
+}
+
+// HasTarget reports whether the expression includes a comparison.
+func (e *Expr) HasTarget() bool {
+	return e.right != nil
+}
+
+// Roll evaluates the expression.
+func (e *Expr) Roll(r *Roller) (Result, error) {
+	res := Result{Notation: e.notation}
+
+	total, detail, err := e.left.eval(r, &res)
+	if err != nil {
+		return Result{}, err
+	}
+	res.Total = total
+	res.Detail = detail
+
+	if e.right != nil {
+		target, _, err := e.right.eval(r, &res)
+		if err != nil {
+			return Result{}, err
+		}
+		res.HasTarget = true
+		res.Op = e.op
+		res.Target = target
+		res.Success = compare(total, e.op, target)
+	}
+	return res, nil
+}
+
+func compare(a int, op string, b int) bool {
+	switch op {
+	case "<=":
+		return a <= b
+	case "<":
+		return a < b
+	case ">=":
+		return a >= b
+	case ">":
+		return a > b
+	default:
+		return a == b
+	}
+}
+
+// ErrDivisionByZero is returned when an expression divides by zero.
+var ErrDivisionByZero = errors.New("dice: division by zero")
+
+// node is an evaluable piece of an expression.
+// eval returns the value and a detail string with rolled dice filled in.
+type node interface {
+	eval(r *Roller, res *Result) (int, string, error)
+}
+
+type numberNode int
+
+func (n numberNode) eval(*Roller, *Result) (int, string, error) {
+	return int(n), strconv.Itoa(int(n)), nil
+}
+
+type negNode struct{ x node }
+
+func (n negNode) eval(r *Roller, res *Result) (int, string, error) {
+	v, d, err := n.x.eval(r, res)
+	return -v, "-" + d, err
+}
+
+type parenNode struct{ x node }
+
+func (n parenNode) eval(r *Roller, res *Result) (int, string, error) {
+	v, d, err := n.x.eval(r, res)
+	return v, "(" + d + ")", err
+}
+
+type binaryNode struct {
+	op          byte
+	left, right node
+}
+
+func (n binaryNode) eval(r *Roller, res *Result) (int, string, error) {
+	a, ad, err := n.left.eval(r, res)
+	if err != nil {
+		return 0, "", err
+	}
+	b, bd, err := n.right.eval(r, res)
+	if err != nil {
+		return 0, "", err
+	}
+	detail := ad + string(n.op) + bd
+	switch n.op {
+	case '+':
+		return a + b, detail, nil
+	case '-':
+		return a - b, detail, nil
+	case '*':
+		return a * b, detail, nil
+	default:
+		if b == 0 {
+			return 0, "", ErrDivisionByZero
+		}
+		return floorDiv(a, b), detail, nil
+	}
+}
+
+// floorDiv divides rounding toward negative infinity (端数切り捨て).
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+type diceNode struct {
+	count, sides int
+	keep         string // "", "KH", "KL", "DH", "DL"
+	keepN        int
+}
+
+func (n diceNode) notation() string {
+	s := strconv.Itoa(n.count) + "D" + strconv.Itoa(n.sides)
+	if n.keep != "" {
+		s += n.keep + strconv.Itoa(n.keepN)
+	}
+	return s
+}
+
+func (n diceNode) eval(r *Roller, res *Result) (int, string, error) {
+	dice := make([]Die, n.count)
+	for i := range dice {
+		dice[i] = Die{Value: r.die(n.sides)}
+	}
+	markDropped(dice, n.keep, n.keepN)
+
+	total := 0
+	values := make([]string, len(dice))
+	for i, d := range dice {
+		if d.Dropped {
+			values[i] = "(" + strconv.Itoa(d.Value) + ")"
+			continue
+		}
+		total += d.Value
+		values[i] = strconv.Itoa(d.Value)
+	}
+
+	res.Rolls = append(res.Rolls, DiceRoll{
+		Notation: n.notation(),
+		Sides:    n.sides,
+		Dice:     dice,
+		Total:    total,
+	})
+	return total, n.notation() + "[" + strings.Join(values, ",") + "]", nil
+}
+
+// markDropped flags dice excluded by a keep/drop modifier.
+// Ties are broken by position so results are deterministic.
+func markDropped(dice []Die, keep string, n int) {
+	if keep == "" {
+		return
+	}
+	order := make([]int, len(dice))
+	for i := range order {
+		order[i] = i
+	}
+	// Stable insertion sort by value ascending
+	for i := 1; i < len(order); i++ {
+		for j := i; j > 0 && dice[order[j]].Value < dice[order[j-1]].Value; j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+
+	var drop []int
+	switch keep {
+	case "KH":
+		drop = order[:max(0, len(order)-n)]
+	case "KL":
+		drop = order[min(n, len(order)):]
+	case "DL":
+		drop = order[:min(n, len(order))]
+	case "DH":
+		drop = order[max(0, len(order)-n):]
+	}
+	for _, i := range drop {
+		dice[i].Dropped = true
+	}
+}
+
+// SyntaxError describes invalid notation.
+type SyntaxError struct {
+	Notation string
+	Pos      int
+	Msg      string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("dice: %s at position %d in %q", e.Msg, e.Pos, e.Notation)
+}
@@ -0,0 +1,147 @@
+package dice
+
+import (
+	"errors"
+	"testing"
+)
+
+// fixedSource returns a fixed sequence of die faces (1-based), cycling.
+type fixedSource struct {
+	faces []int
+	i     int
+}
+
+func (s *fixedSource) IntN(n int) int {
+	v := s.faces[s.i%len(s.faces)]
+	s.i++
+	return (v - 1) % n
+}
+
+func rollWith(t *testing.T, notation string, faces ...int) Result {
+	t.Helper()
+	res, err := NewRoller(&fixedSource{faces: faces}).Roll(notation)
+	if err != nil {
+		t.Fatalf("Roll(%q): %v", notation, err)
+	}
+	return res
+}
+
+func TestRoll(t *testing.T) {
+	tests := []struct {
+		notation string
+		faces    []int
+		want     int
+		detail   string
+	}{
+		{"3D6", []int{4, 2, 6}, 12, "3D6[4,2,6]"},
+		{"2D6+6", []int{3, 5}, 14, "2D6[3,5]+6"},
+		{"3d6+3", []int{1, 1, 1}, 6, "3D6[1,1,1]+3"},
+		{"(1D6+1)*2", []int{3}, 8, "(1D6[3]+1)*2"},
+		{"D6", []int{5}, 5, "1D6[5]"},
+		{"1D%", []int{42}, 42, "1D100[42]"},
+		{"4D6KH3", []int{1, 5, 3, 6}, 14, "4D6KH3[(1),5,3,6]"},
+		{"4D6K3", []int{1, 5, 3, 6}, 14, "4D6KH3[(1),5,3,6]"},
+		{"4D6DL1", []int{2, 2, 4, 6}, 12, "4D6DL1[(2),2,4,6]"},
+		{"2D20KL1", []int{15, 7}, 7, "2D20KL1[(15),7]"},
+		{"3D6DH1", []int{6, 1, 6}, 7, "3D6DH1[6,1,(6)]"},
+		{"1D6/2", []int{5}, 2, "1D6[5]/2"},
+		{"-1D4", []int{3}, -3, "-1D4[3]"},
+		{"+1d4", []int{2}, 2, "1D4[2]"},
+		{"1D3+-1D4", []int{2, 3}, -1, "1D3[2]+-1D4[3]"},
+		{"１Ｄ６＋２", []int{1}, 3, "1D6[1]+2"},
+		{"10", nil, 10, "10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			faces := tt.faces
+			if faces == nil {
+				faces = []int{1}
+			}
+			res := rollWith(t, tt.notation, faces...)
+			if res.Total != tt.want {
+				t.Errorf("Total = %d, want %d", res.Total, tt.want)
+			}
+			if res.Detail != tt.detail {
+				t.Errorf("Detail = %q, want %q", res.Detail, tt.detail)
+			}
+		})
+	}
+}
+
+func TestRollComparison(t *testing.T) {
+	res := rollWith(t, "1d100<=65", 42)
+	if !res.HasTarget || res.Op != "<=" || res.Target != 65 || !res.Success {
+		t.Errorf("unexpected comparison result: %+v", res)
+	}
+	if got := res.Breakdown(); got != "1D100[42] = 42 <= 65" {
+		t.Errorf("Breakdown() = %q", got)
+	}
+
+	res = rollWith(t, "1D100≦30", 42)
+	if res.Success {
+		t.Error("expected 42 <= 30 to fail")
+	}
+
+	res = rollWith(t, "2D6>=7", 3, 4)
+	if !res.Success {
+		t.Error("expected 7 >= 7 to succeed")
+	}
+}
+
+func TestRollRecordsDice(t *testing.T) {
+	res := rollWith(t, "1D6+4D6DL1", 2, 1, 5, 3, 6)
+	if len(res.Rolls) != 2 {
+		t.Fatalf("expected 2 dice rolls, got %d", len(res.Rolls))
+	}
+	second := res.Rolls[1]
+	if second.Notation != "4D6DL1" || second.Total != 14 {
+		t.Errorf("unexpected second roll: %+v", second)
+	}
+	if !second.Dice[0].Dropped {
+		t.Error("expected the 1 to be dropped")
+	}
+}
+
+func TestSeededIsDeterministic(t *testing.T) {
+	a, _ := Seeded(42).Roll("10D100")
+	b, _ := Seeded(42).Roll("10D100")
+	if a.Detail != b.Detail {
+		t.Errorf("same seed gave %q and %q", a.Detail, b.Detail)
+	}
+	for _, d := range a.Rolls[0].Dice {
+		if d.Value < 1 || d.Value > 100 {
+			t.Errorf("die out of range: %d", d.Value)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, notation := range []string{
+		"",
+		"3D",
+		"3D6+",
+		"(1D6",
+		"1D6)",
+		"0D6",
+		"1D0",
+		"1001D6",
+		"4D6KH",
+		"1D6<=",
+		"abc",
+	} {
+		t.Run(notation, func(t *testing.T) {
+			_, err := Parse(notation)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("Parse(%q) error = %v, want SyntaxError", notation, err)
+			}
+		})
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	_, err := Roll("1D6/0")
+	if !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("expected ErrDivisionByZero, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package dice
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// formulaVariable matches variable keys in a formula
+var formulaVariable = regexp.MustCompile(`[A-Z]{3}`)
+
+// FormulaVariables returns the variable keys a formula uses, in order
+func FormulaVariables(formula string) []string {
+	return formulaVariable.FindAllString(formula, -1)
+}
+
+// EvalFormula evaluates a formula such as "DEX*2" or "(DEX+EDU)/5",
+// replacing three-letter keys with the values lookup returns. Formulas may
+// use integers, keys and arithmetic, but no dice or comparisons.
+func EvalFormula(formula string, lookup func(key string) (int, bool)) (int, error) {
+	var unknown string
+	expr := formulaVariable.ReplaceAllStringFunc(formula, func(key string) string {
+		v, ok := lookup(key)
+		if !ok {
+			unknown = key
+			return key
+		}
+		return "(" + strconv.Itoa(v) + ")"
+	})
+	if unknown != "" {
+		return 0, fmt.Errorf("formula %q: unknown characteristic %s", formula, unknown)
+	}
+	e, err := Parse(expr)
+	if err != nil {
+		return 0, fmt.Errorf("formula %q: %w", formula, err)
+	}
+	if e.HasTarget() {
+		return 0, fmt.Errorf("formula %q must not contain a comparison", formula)
+	}
+	res, err := e.Roll(Default())
+	if err != nil {
+		return 0, fmt.Errorf("formula %q: %w", formula, err)
+	}
+	if len(res.Rolls) > 0 {
+		return 0, fmt.Errorf("formula %q must not roll dice", formula)
+	}
+	return res.Total, nil
+}
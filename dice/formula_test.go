@@ -0,0 +1,44 @@
+package dice
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEvalFormula(t *testing.T) {
+	values := map[string]int{"DEX": 11, "EDU": 14}
+	lookup := func(key string) (int, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+	tests := []struct {
+		formula string
+		want    int
+		err     bool
+	}{
+		{"25", 25, false},
+		{"DEX*2", 22, false},
+		{"EDU×5", 70, false},
+		{"(DEX+EDU)/5", 5, false},
+		{"-DEX+20", 9, false},
+		{"LUK*5", 0, true},
+		{"1D6", 0, true},
+		{"DEX>=3", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := EvalFormula(tt.formula, lookup)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("EvalFormula(%q) = %d, %v; want %d, err=%v", tt.formula, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestFormulaVariables(t *testing.T) {
+	if got := FormulaVariables("EDU*2+STR*2"); !slices.Equal(got, []string{"EDU", "STR"}) {
+		t.Errorf("Expected [EDU STR], got %v", got)
+	}
+	if got := FormulaVariables("25"); len(got) != 0 {
+		t.Errorf("Expected no variables, got %v", got)
+	}
+}
@@ -0,0 +1,257 @@
+package dice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse parses dice notation into an expression.
+func Parse(notation string) (*Expr, error) {
+	p := &parser{src: normalize(notation)}
+	if p.src == "" {
+		return nil, &SyntaxError{Notation: notation, Msg: "empty expression"}
+	}
+
+	left, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	e := &Expr{left: left}
+
+	if op := p.comparison(); op != "" {
+		right, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		e.op = op
+		e.right = right
+	}
+
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+	e.notation = p.src
+	return e, nil
+}
+
+// normalize folds full-width characters and common symbols to ASCII,
+// removes whitespace and upper-cases the notation.
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '！' && r <= '～':
+			r = r - '！' + '!'
+		case r == '≦' || r == '≤':
+			b.WriteString("<=")
+			continue
+		case r == '≧' || r == '≥':
+			b.WriteString(">=")
+			continue
+		case r == '×':
+			r = '*'
+		case r == '÷':
+			r = '/'
+		case r == ' ' || r == '\t' || r == '　':
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Notation: p.src, Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+// expr := term (("+"|"-") term)*
+func (p *parser) expr() (node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+// term := unary (("*"|"/") unary)*
+func (p *parser) term() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+// unary := ("-"|"+") unary | primary
+func (p *parser) unary() (node, error) {
+	// Leading plus appears in damage bonuses like "+1D4"
+	if p.peek() == '+' {
+		p.pos++
+		return p.unary()
+	}
+	if p.peek() == '-' {
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{x: x}, nil
+	}
+	return p.primary()
+}
+
+// primary := "(" expr ")" | [number] "D" sides [modifier] | number
+func (p *parser) primary() (node, error) {
+	switch c := p.peek(); {
+	case c == '(':
+		p.pos++
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return parenNode{x: x}, nil
+	case c == 'D':
+		return p.dice(1)
+	case isDigit(c):
+		n, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() == 'D' {
+			return p.dice(n)
+		}
+		return numberNode(n), nil
+	case c == 0:
+		return nil, p.errorf("unexpected end of expression")
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+// dice parses "D" sides [modifier] after the count.
+func (p *parser) dice(count int) (node, error) {
+	p.pos++ // 'D'
+	var sides int
+	if p.peek() == '%' {
+		p.pos++
+		sides = 100
+	} else {
+		if !isDigit(p.peek()) {
+			return nil, p.errorf("missing number of sides")
+		}
+		n, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		sides = n
+	}
+
+	if count < 1 || count > MaxDice {
+		return nil, p.errorf("dice count must be between 1 and %d", MaxDice)
+	}
+	if sides < 1 || sides > MaxSides {
+		return nil, p.errorf("dice sides must be between 1 and %d", MaxSides)
+	}
+
+	d := diceNode{count: count, sides: sides}
+	if keep := p.modifier(); keep != "" {
+		if !isDigit(p.peek()) {
+			return nil, p.errorf("missing count after %s", keep)
+		}
+		n, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		d.keep = keep
+		d.keepN = n
+	}
+	return d, nil
+}
+
+// modifier consumes a keep/drop modifier and returns its canonical form.
+func (p *parser) modifier() string {
+	rest := p.src[p.pos:]
+	for _, m := range []string{"KH", "KL", "DH", "DL"} {
+		if strings.HasPrefix(rest, m) {
+			p.pos += 2
+			return m
+		}
+	}
+	// Bare "K" is keep highest
+	if strings.HasPrefix(rest, "K") {
+		p.pos++
+		return "KH"
+	}
+	return ""
+}
+
+// comparison consumes a comparison operator if present.
+func (p *parser) comparison() string {
+	rest := p.src[p.pos:]
+	for _, op := range []string{"<=", ">=", "==", "<", ">", "="} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			if op == "==" {
+				return "="
+			}
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *parser) number() (int, error) {
+	start := p.pos
+	for isDigit(p.peek()) {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid number")
+	}
+	return n, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -11,8 +12,11 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"charaxiv/storage"
@@ -121,9 +125,45 @@ func main() {
 		slog.Info("Coalesce backend: disk", "dataDir", dataDir)
 	}
 
+	// Choose write buffer: a shared Postgres buffer lets replicas see each other's pending writes
+	var buffer coalesce.Buffer
+	if dsn := os.Getenv("BUFFER_DATABASE_URL"); dsn != "" {
+		pgBuffer, err := coalesce.NewPostgresBuffer(context.Background(), dsn)
+		if err != nil {
+			slog.Error("Failed to initialize Postgres write buffer", "error", err)
+			os.Exit(1)
+		}
+		buffer = pgBuffer
+		slog.Info("Coalesce buffer: Postgres")
+	} else {
+		slog.Info("Coalesce buffer: SQLite", "path", filepath.Join(dataDir, "buffer.db"))
+	}
+
+	// Flush policy: interactive edits are served from merged views and persisted
+	// once they are old enough or numerous enough, not on every read
+	flushPolicy := coalesce.FlushPolicy{MaxAge: 30 * time.Second, MaxPending: 100}
+	if v := os.Getenv("FLUSH_MAX_AGE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Error("Invalid FLUSH_MAX_AGE", "value", v, "error", err)
+			os.Exit(1)
+		}
+		flushPolicy.MaxAge = d
+	}
+	if v := os.Getenv("FLUSH_MAX_PENDING"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			slog.Error("Invalid FLUSH_MAX_PENDING", "value", v, "error", err)
+			os.Exit(1)
+		}
+		flushPolicy.MaxPending = n
+	}
+
 	cs, err := coalesce.New(coalesce.Config{
 		DBPath:  filepath.Join(dataDir, "buffer.db"),
+		Buffer:  buffer,
 		Backend: backend,
+		Flush:   flushPolicy,
 	})
 	if err != nil {
 		slog.Error("Failed to initialize coalesce store", "error", err)
@@ -131,6 +171,13 @@ func main() {
 	}
 	defer cs.Close()
 
+	// Stop on SIGINT/SIGTERM so pending writes can be flushed before exit
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background flusher for writes that are due under the policy
+	go cs.Run(ctx, 5*time.Second)
+
 	// Initialize storage
 	var store storage.Storage
 	if bucket := os.Getenv("GCS_BUCKET"); bucket != "" {
@@ -151,7 +198,7 @@ func main() {
 	}
 
 	// Build server with application routes
-	r := NewServer(cs)
+	r := NewServer(cs, store)
 
 	// Dev mode: proxy to reloader (including WebSocket)
 	if os.Getenv("DEV") == "1" {
@@ -229,24 +276,35 @@ func main() {
 
 	slog.Info("Starting server", "port", port)
 
+	ln, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		slog.Error("Failed to listen", "error", err)
+		os.Exit(1)
+	}
+
 	if devMode {
-		// Use a listener so we can trigger reload AFTER server starts listening
-		ln, err := net.Listen("tcp", ":"+port)
-		if err != nil {
-			slog.Error("Failed to listen", "error", err)
-			os.Exit(1)
-		}
 		slog.Debug("Server listening, triggering reload")
 		// Now trigger reload - server is definitely listening
 		go http.Get("http://localhost:8001/reload")
-		if err := http.Serve(ln, r); err != nil {
-			slog.Error("Server error", "error", err)
-			os.Exit(1)
-		}
-	} else {
-		if err := http.ListenAndServe(":"+port, r); err != nil {
-			slog.Error("Server error", "error", err)
-			os.Exit(1)
-		}
+	}
+
+	srv := &http.Server{Handler: r}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Server error", "error", err)
+		os.Exit(1)
+	}
+
+	// Persist everything still buffered before the instance goes away
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cs.FlushAll(flushCtx); err != nil {
+		slog.Error("Failed to flush pending writes on shutdown", "error", err)
 	}
 }
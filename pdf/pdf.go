@@ -0,0 +1,225 @@
+// Package pdf lays out printable A4 documents with an embedded Japanese font.
+// Documents are built top to bottom from headings, tables and text, breaking
+// pages as needed. Rendering is pure Go, so it needs no fonts or tools on the
+// host.
+package pdf
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/signintech/gopdf"
+)
+
+// gothic is M+ 1p (SIL Open Font License, see OFL.txt). Documents embed the
+// subset of its glyphs they use.
+//
+//go:embed MPLUS1p-Regular.ttf
+var gothic []byte
+
+const fontFamily = "gothic"
+
+// Page geometry in millimetres
+const (
+	PageWidth    = 210.0
+	PageHeight   = 297.0
+	Margin       = 12.0
+	ContentWidth = PageWidth - 2*Margin
+)
+
+// Font sizes in points
+const (
+	titleSize   = 16.0
+	headingSize = 11.0
+	bodySize    = 8.5
+)
+
+// mmPerPoint converts font sizes to layout units
+const mmPerPoint = 25.4 / 72
+
+// Column describes one table column
+type Column struct {
+	Label string
+	Width float64 // millimetres
+	Right bool    // right-align, for numbers
+}
+
+// Document is an A4 document under construction. Layout errors are kept
+// and returned by WriteTo, so building code need not check every call.
+type Document struct {
+	pdf gopdf.GoPdf
+	y   float64
+	err error
+}
+
+// New starts a document with the given title in its metadata
+func New(title string) (*Document, error) {
+	d := &Document{}
+	d.pdf.Start(gopdf.Config{Unit: gopdf.UnitMM, PageSize: *gopdf.PageSizeA4})
+	d.pdf.SetInfo(gopdf.PdfInfo{Title: title, Creator: "charaxiv"})
+	if err := d.pdf.AddTTFFontData(fontFamily, gothic); err != nil {
+		return nil, fmt.Errorf("load font: %w", err)
+	}
+	d.newPage()
+	return d, nil
+}
+
+// newPage starts a page and moves to its top margin
+func (d *Document) newPage() {
+	d.pdf.AddPage()
+	d.y = Margin
+}
+
+// ensure breaks the page unless h millimetres fit above the bottom margin
+func (d *Document) ensure(h float64) bool {
+	if d.y+h <= PageHeight-Margin {
+		return false
+	}
+	d.newPage()
+	return true
+}
+
+// lineHeight returns the line height for a font size
+func lineHeight(size float64) float64 {
+	return size * mmPerPoint * 1.5
+}
+
+// cell draws text in a box at the current line. Right-aligned text ends at
+// x+w; other text starts at x. Text that does not fit is cut short.
+func (d *Document) cell(x, w, size float64, text string, right bool) {
+	if d.err != nil || text == "" {
+		return
+	}
+	if err := d.pdf.SetFont(fontFamily, "", size); err != nil {
+		d.err = err
+		return
+	}
+	text = d.fit(text, w-1)
+	align := gopdf.Left | gopdf.Middle
+	if right {
+		align = gopdf.Right | gopdf.Middle
+	}
+	d.pdf.SetXY(x+0.5, d.y)
+	rect := &gopdf.Rect{W: w - 1, H: lineHeight(size)}
+	if err := d.pdf.CellWithOption(rect, text, gopdf.CellOption{Align: align}); err != nil {
+		d.err = fmt.Errorf("draw %q: %w", text, err)
+	}
+}
+
+// fit shortens text with an ellipsis until it is at most w wide
+func (d *Document) fit(text string, w float64) string {
+	if width, err := d.pdf.MeasureTextWidth(text); err != nil || width <= w {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		s := string(runes) + "…"
+		if width, err := d.pdf.MeasureTextWidth(s); err == nil && width <= w {
+			return s
+		}
+	}
+	return ""
+}
+
+// Title draws the document title
+func (d *Document) Title(text string) {
+	h := lineHeight(titleSize)
+	d.ensure(h)
+	d.cell(Margin, ContentWidth, titleSize, text, false)
+	d.y += h + 2
+}
+
+// Heading draws a section heading with a rule under it
+func (d *Document) Heading(text string) {
+	h := lineHeight(headingSize)
+	// Keep a heading with at least two lines of what follows
+	d.ensure(h + 3*lineHeight(bodySize))
+	d.y += 2
+	d.cell(Margin, ContentWidth, headingSize, text, false)
+	d.y += h
+	d.pdf.SetLineWidth(0.3)
+	d.pdf.Line(Margin, d.y, PageWidth-Margin, d.y)
+	d.y += 1
+}
+
+// Table draws rows under a header row, repeating the header on each page
+// the table continues onto. Rows are shaded alternately for reading across.
+func (d *Document) Table(columns []Column, rows [][]string) {
+	h := lineHeight(bodySize)
+	var width float64
+	for _, c := range columns {
+		width += c.Width
+	}
+	header := func() {
+		d.pdf.SetFillColor(220, 220, 220)
+		d.pdf.RectFromUpperLeftWithStyle(Margin, d.y, width, h, "F")
+		x := Margin
+		for _, c := range columns {
+			d.cell(x, c.Width, bodySize, c.Label, c.Right)
+			x += c.Width
+		}
+		d.y += h
+	}
+
+	d.ensure(2 * h)
+	header()
+	for i, row := range rows {
+		if d.ensure(h) {
+			header()
+		}
+		if i%2 == 1 {
+			d.pdf.SetFillColor(245, 245, 245)
+			d.pdf.RectFromUpperLeftWithStyle(Margin, d.y, width, h, "F")
+		}
+		x := Margin
+		for j, c := range columns {
+			if j < len(row) {
+				d.cell(x, c.Width, bodySize, row[j], c.Right)
+			}
+			x += c.Width
+		}
+		d.y += h
+	}
+	d.pdf.SetLineWidth(0.1)
+	d.pdf.Line(Margin, d.y, Margin+width, d.y)
+	d.y += 2
+}
+
+// Text draws wrapped text, keeping its line breaks
+func (d *Document) Text(text string) {
+	if d.err != nil {
+		return
+	}
+	if err := d.pdf.SetFont(fontFamily, "", bodySize); err != nil {
+		d.err = err
+		return
+	}
+	h := lineHeight(bodySize)
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		lines := []string{""}
+		if paragraph != "" {
+			var err error
+			if lines, err = d.pdf.SplitText(paragraph, ContentWidth-1); err != nil {
+				d.err = fmt.Errorf("wrap text: %w", err)
+				return
+			}
+		}
+		for _, line := range lines {
+			d.ensure(h)
+			d.cell(Margin, ContentWidth, bodySize, line, false)
+			d.y += h
+		}
+	}
+	d.y += 2
+}
+
+// WriteTo writes the finished document, or the first layout error
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.pdf.WriteTo(w)
+}
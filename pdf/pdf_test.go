@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestTableBreaksPages(t *testing.T) {
+	d, err := New("テスト")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	d.Title("探索者")
+	d.Heading("技能")
+	var rows [][]string
+	for i := range 100 {
+		rows = append(rows, []string{"目星", strconv.Itoa(i)})
+	}
+	d.Table([]Column{{Label: "技能", Width: 40}, {Label: "値", Width: 20, Right: true}}, rows)
+	d.Text("一行目\n\n三行目")
+
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if n := d.pdf.GetNumberOfPages(); n < 2 {
+		t.Errorf("Expected the table to continue onto a second page, got %d pages", n)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Error("Expected a PDF header")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("/FontFile2")) {
+		t.Error("Expected the font to be embedded")
+	}
+}
+
+func TestFitShortensLongText(t *testing.T) {
+	d, err := New("テスト")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	d.pdf.SetFont(fontFamily, "", bodySize)
+	if got := d.fit("目星", 50); got != "目星" {
+		t.Errorf("Expected short text unchanged, got %q", got)
+	}
+	got := d.fit("クトゥルフ神話技能クトゥルフ神話技能", 15)
+	if w, _ := d.pdf.MeasureTextWidth(got); w > 15 || []rune(got)[len([]rune(got))-1] != '…' {
+		t.Errorf("Expected text cut to 15mm with an ellipsis, got %q (%.1fmm)", got, w)
+	}
+}
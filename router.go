@@ -7,25 +7,38 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 
 	"charaxiv/routes/cthulhu6"
+	"charaxiv/routes/cthulhu7"
+	"charaxiv/routes/emoklore"
+	"charaxiv/routes/gamesystem"
+	"charaxiv/storage"
 	"charaxiv/storage/coalesce"
 )
 
 // AppRouter creates the application router with all game system routes mounted.
 // This is the single source of truth for application route configuration.
 // Infrastructure routes (health, static, dev proxy) are added separately in main.
-func AppRouter(cs *coalesce.Store) chi.Router {
+func AppRouter(cs *coalesce.Store, images storage.Storage) chi.Router {
 	r := chi.NewRouter()
 
-	// Mount cthulhu6 system routes
-	cthulhu6Store := cthulhu6.NewStore(cs)
-	r.Mount("/cthulhu6", cthulhu6.Routes(cthulhu6Store))
+	// Register game systems in display order
+	systems := gamesystem.NewRegistry(
+		cthulhu6.NewSystem(cs, images),
+		cthulhu7.NewSystem(cs),
+		emoklore.NewSystem(cs),
+	)
+	for _, sys := range systems.Systems() {
+		r.Mount("/"+sys.ID(), sys.Router())
+	}
+
+	// Character index, search, export and import across systems
+	r.Mount("/", gamesystem.Routes(systems))
 
 	return r
 }
 
 // NewServer creates a fully configured HTTP server with all routes.
 // This includes both application routes and infrastructure routes.
-func NewServer(cs *coalesce.Store) chi.Router {
+func NewServer(cs *coalesce.Store, images storage.Storage) chi.Router {
 	r := chi.NewRouter()
 
 	// Middleware
@@ -42,7 +55,7 @@ func NewServer(cs *coalesce.Store) chi.Router {
 	})
 
 	// Mount application routes
-	r.Mount("/", AppRouter(cs))
+	r.Mount("/", AppRouter(cs, images))
 
 	return r
 }
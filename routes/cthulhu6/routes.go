@@ -1,7 +1,13 @@
 package cthulhu6
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -31,23 +37,116 @@ func buildPageContext(store *Store, charID, basePath string) shared.PageContext
 	for _, id := range memoIDs {
 		ctx.Memos[id] = store.GetMemo(charID, id)
 	}
+	ctx.Portraits = store.images != nil
 	return ctx
 }
 
+// withPortrait points the page context at the pinned portrait, if any.
+// version changes the URL after an upload so browsers fetch the new image.
+func withPortrait(ctx context.Context, store *Store, charID string, pc shared.PageContext, version string) shared.PageContext {
+	if store.HasPortrait(ctx, charID) {
+		pc.Portrait = pc.API("/api/portrait")
+		if version != "" {
+			pc.Portrait += "?v=" + version
+		}
+	}
+	return pc
+}
+
+// weaponsPanel renders the weapons panel for an OOB swap
+func weaponsPanel(store *Store, charID, basePath string) templ.Component {
+	pc := buildPageContext(store, charID, basePath)
+	status := store.GetStatus(charID)
+	skills := store.GetSkills(charID)
+	weapons := cthulhu6.BuildWeapons(status, skills, store.GetWeapons(charID), store.GetDamageRolls(charID))
+	return components.Cthulhu6WeaponsPanel(pc, weapons, true)
+}
+
+// inventoryPanel renders the inventory panel for an OOB swap
+func inventoryPanel(store *Store, charID, basePath string) templ.Component {
+	pc := buildPageContext(store, charID, basePath)
+	status := store.GetStatus(charID)
+	skills := store.GetSkills(charID)
+	inv := cthulhu6.BuildInventory(status, skills, store.GetInventory(charID), store.GetFinances(charID))
+	return components.Cthulhu6InventoryPanel(pc, inv, true)
+}
+
+// statusFragments renders the status panel plus the OOB updates the changed variables need.
+// Variables referenced by skill formulas (DEX for 回避, EDU for 母国語 under the official
+// rules) affect skill initial values; variables referenced by the point formulas affect
+// remaining skill points; any other change can still affect the characteristic warnings.
+func statusFragments(state shared.SheetState, status *cthulhu6.Status, changes map[string]int) templ.Component {
+	skillsChanged, pointsChanged := false, false
+	for key := range changes {
+		skillsChanged = skillsChanged || status.SkillsDependOn(key)
+		pointsChanged = pointsChanged || status.PointsDependOn(key)
+	}
+	switch {
+	case skillsChanged:
+		return components.Cthulhu6StatusPanelWithSkills(state)
+	case pointsChanged:
+		return components.Cthulhu6StatusPanelWithPoints(state)
+	default:
+		return components.Cthulhu6StatusPanelWithWarnings(state)
+	}
+}
+
+// checkModifier parses the optional bonus/penalty applied to a check target
+func checkModifier(r *http.Request) int {
+	modifier := 0
+	fmt.Sscanf(r.FormValue("modifier"), "%d", &modifier)
+	return modifier
+}
+
+// pieceName names a character piece for online tabletop tools. Sheets have
+// no stored name yet, so the occupation stands in for it.
+func pieceName(skills *cthulhu6.Skills) string {
+	if o, ok := cthulhu6.OccupationByID(skills.Occupation.ID); ok {
+		return o.Name
+	}
+	return "探索者"
+}
+
+// sheetMemos returns the memos printed on the PDF sheet in sheet order.
+// Secret memos are included only when asked for by someone who can see them
+// on the sheet.
+func sheetMemos(pc shared.PageContext, secret bool) []cthulhu6.SheetMemo {
+	secret = secret && !pc.IsReadOnly()
+	var memos []cthulhu6.SheetMemo
+	for _, m := range []struct {
+		id, title string
+		secret    bool
+	}{
+		{"public-memo", "公開メモ", false},
+		{"secret-memo", "秘匿メモ", true},
+		{"scenario-public-memo", "シナリオ公開メモ", false},
+		{"scenario-secret-memo", "シナリオ秘匿メモ", true},
+	} {
+		if !m.secret || secret {
+			memos = append(memos, cthulhu6.SheetMemo{Title: m.title, Text: pc.GetMemo(m.id)})
+		}
+	}
+	return memos
+}
+
 // Routes returns a chi.Router with all cthulhu6-specific routes.
 func Routes(store *Store) chi.Router {
 	r := chi.NewRouter()
 
 	// For now, use a fixed character ID until we have proper routing
-	const charID = "demo"
+	const charID = DefaultDocument
 	const basePath = "/cthulhu6"
 
 	// Character sheet
 	r.Get("/", html(func(r *http.Request) templ.Component {
-		pc := buildPageContext(store, charID, basePath)
+		pc := withPortrait(r.Context(), store, charID, buildPageContext(store, charID, basePath), "")
 		status := store.GetStatus(charID)
 		skills := store.GetSkills(charID)
 		state := cthulhu6.BuildSheetState(pc, status, skills)
+		state.Checks = cthulhu6.BuildCheckRolls(store.GetChecks(charID))
+		state.Growth = cthulhu6.BuildGrowthReports(store.GetGrowthReports(charID))
+		state.Weapons = cthulhu6.BuildWeapons(status, skills, store.GetWeapons(charID), store.GetDamageRolls(charID))
+		state.Inventory = cthulhu6.BuildInventory(status, skills, store.GetInventory(charID), store.GetFinances(charID))
 		return pages.Cthulhu6Sheet(state)
 	}))
 
@@ -85,24 +184,15 @@ func Routes(store *Store) chi.Router {
 			return
 		}
 
-		// Check if value actually changed
 		status := store.GetStatus(charID)
-		if v, ok := status.Variables[key]; ok {
-			clampedValue := value
-			if clampedValue < v.Min {
-				clampedValue = v.Min
-			}
-			if clampedValue > v.Max {
-				clampedValue = v.Max
-			}
-			if v.Base == clampedValue {
-				w.WriteHeader(http.StatusNoContent)
-				return
-			}
+		if _, ok := status.Variables[key]; !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
 		}
 
-		updated := store.SetVariableBase(charID, key, value)
-		if updated == nil {
+		// Rejected values re-render the panel so the input reverts
+		changes, err := store.SetVariableBase(charID, key, value)
+		if err == nil && len(changes) == 0 {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
@@ -112,16 +202,341 @@ func Routes(store *Store) chi.Router {
 		skills := store.GetSkills(charID)
 		state := cthulhu6.BuildSheetState(pc, status, skills)
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		statusFragments(state, status, changes).Render(r.Context(), w)
+	})
+
+	// Switch stat generation mode (roll, 4d6, pointbuy, array)
+	r.Post("/api/status/generation", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		g := cthulhu6.Generation{Mode: cthulhu6.GenerationMode(r.FormValue("mode"))}
+		fmt.Sscanf(r.FormValue("budget"), "%d", &g.Budget)
 
-		switch key {
-		case "DEX", "EDU":
-			components.Cthulhu6StatusPanelWithSkills(state).Render(r.Context(), w)
-		case "INT":
-			components.Cthulhu6StatusPanelWithPoints(state).Render(r.Context(), w)
-		default:
-			components.Cthulhu6StatusPanel(state, true).Render(r.Context(), w)
+		if err := store.SetGeneration(charID, g); err != nil {
+			return shared.Empty()
 		}
-	})
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanelWithSkills(state)
+	}))
+
+	// Switch the era preset, migrating allocated skill points
+	r.Post("/api/status/era", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		if err := store.SetEra(charID, cthulhu6.Era(r.FormValue("era"))); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanelWithSkills(state)
+	}))
+
+	// Select a house-rule profile for derived values
+	r.Post("/api/status/rules", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		if err := store.SetRuleProfile(charID, cthulhu6.RuleProfile(r.FormValue("profile"))); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanelWithSkills(state)
+	}))
+
+	// Edit one formula of the custom rule set
+	r.Post("/api/status/rules/formula", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		// Rejected formulas re-render the panel so the input reverts
+		store.SetRuleFormula(charID, r.FormValue("group"), r.FormValue("key"), r.FormValue("formula"))
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanelWithSkills(state)
+	}))
+
+	// Roll all status variables from their dice formulas
+	r.Post("/api/status/random", html(func(r *http.Request) templ.Component {
+		if _, err := store.RollVariables(charID); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanelWithSkills(state)
+	}))
+
+	// Reroll a single status variable
+	r.Post("/api/status/{key}/reroll", html(func(r *http.Request) templ.Component {
+		key := strings.TrimPrefix(chi.URLParam(r, "key"), "status-")
+
+		if _, err := store.RollVariables(charID, key); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanelWithSkills(state)
+	}))
+
+	// Characteristic check (e.g. STR×5)
+	r.Post("/api/status/{key}/check", html(func(r *http.Request) templ.Component {
+		key := strings.TrimPrefix(chi.URLParam(r, "key"), "status-")
+		multiplier := 5
+		fmt.Sscanf(r.FormValue("multiplier"), "%d", &multiplier)
+		if multiplier < 1 || multiplier > 5 {
+			multiplier = 5
+		}
+
+		if _, err := store.CheckVariable(charID, key, multiplier, checkModifier(r)); err != nil {
+			return shared.Empty()
+		}
+		return components.Cthulhu6CheckHistory(cthulhu6.BuildCheckRolls(store.GetChecks(charID)), true)
+	}))
+
+	// Resolve growth checks for every skill marked Grow
+	r.Post("/api/growth/resolve", html(func(r *http.Request) templ.Component {
+		if _, err := store.ResolveGrowth(charID); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		state.Growth = cthulhu6.BuildGrowthReports(store.GetGrowthReports(charID))
+		return components.Cthulhu6GrowthUpdateFragments(state)
+	}))
+
+	// SAN check with a loss expression like "1/1D6"
+	r.Post("/api/sanity/check", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		if _, err := store.SanCheck(charID, r.FormValue("expr")); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanel(state, true)
+	}))
+
+	// Start a new SAN loss session
+	r.Post("/api/sanity/session/reset", html(func(r *http.Request) templ.Component {
+		store.ResetSanSession(charID)
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanel(state, true)
+	}))
+
+	// Toggle temporary/indefinite insanity
+	r.Post("/api/sanity/flag/{kind}/toggle", html(func(r *http.Request) templ.Component {
+		kind := cthulhu6.InsanityKind(chi.URLParam(r, "kind"))
+		if err := store.ToggleSanityFlag(charID, kind); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanel(state, true)
+	}))
+
+	// Record an insanity, phobia or mania
+	r.Post("/api/sanity/insanity/add", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		kind := cthulhu6.InsanityKind(r.FormValue("kind"))
+		if err := store.AddInsanity(charID, r.FormValue("label"), kind); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanel(state, true)
+	}))
+
+	// Remove a recorded insanity
+	r.Post("/api/sanity/insanity/{index}/delete", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		if !store.DeleteInsanity(charID, index) {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6StatusPanel(state, true)
+	}))
+
+	// Select occupation from the catalog
+	r.Post("/api/occupation", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		if err := store.SetOccupation(charID, r.FormValue("id")); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6OccupationUpdateFragments(state)
+	}))
+
+	// Set the occupation's "choose N" picks; values are "<slot>:<option>"
+	r.Post("/api/occupation/choices", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		picks := make(map[int][]int)
+		for _, v := range r.Form["choice"] {
+			var slot, option int
+			if _, err := fmt.Sscanf(v, "%d:%d", &slot, &option); err == nil {
+				picks[slot] = append(picks[slot], option)
+			}
+		}
+		if err := store.SetOccupationChoices(charID, picks); err != nil {
+			return shared.Empty()
+		}
+
+		pc := buildPageContext(store, charID, basePath)
+		status := store.GetStatus(charID)
+		skills := store.GetSkills(charID)
+		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return components.Cthulhu6OccupationUpdateFragments(state)
+	}))
+
+	// Weapon: add from the catalog, or blank when no catalog ID is given
+	r.Post("/api/weapon/add", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		if err := store.AddWeapon(charID, r.FormValue("catalog")); err != nil {
+			return shared.Empty()
+		}
+		return weaponsPanel(store, charID, basePath)
+	}))
+
+	// Weapon: set a field (name, skill, damage, range, attacks, ammo, malfunction, hp, melee)
+	r.Post("/api/weapon/{index}/{field}/set", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		r.ParseForm()
+		// A rejected value is dropped; the re-render restores the stored one
+		store.SetWeaponField(charID, index, chi.URLParam(r, "field"), r.FormValue("value"))
+		return weaponsPanel(store, charID, basePath)
+	}))
+
+	// Weapon: delete
+	r.Post("/api/weapon/{index}/delete", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		if !store.DeleteWeapon(charID, index) {
+			return shared.Empty()
+		}
+		return weaponsPanel(store, charID, basePath)
+	}))
+
+	// Weapon: attack check against the linked skill
+	r.Post("/api/weapon/{index}/check", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		if _, err := store.CheckWeapon(charID, index, checkModifier(r)); err != nil {
+			return shared.Empty()
+		}
+		return components.Cthulhu6CheckHistory(cthulhu6.BuildCheckRolls(store.GetChecks(charID)), true)
+	}))
+
+	// Weapon: damage roll, adding the damage bonus for melee weapons
+	r.Post("/api/weapon/{index}/damage", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		if _, err := store.RollWeaponDamage(charID, index); err != nil {
+			return shared.Empty()
+		}
+		return weaponsPanel(store, charID, basePath)
+	}))
+
+	// Inventory: add a blank item
+	r.Post("/api/item/add", html(func(r *http.Request) templ.Component {
+		store.AddItem(charID)
+		return inventoryPanel(store, charID, basePath)
+	}))
+
+	// Inventory: set a field (name, quantity, notes, stored)
+	r.Post("/api/item/{index}/{field}/set", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		r.ParseForm()
+		// A rejected value is dropped; the re-render restores the stored one
+		store.SetItemField(charID, index, chi.URLParam(r, "field"), r.FormValue("value"))
+		return inventoryPanel(store, charID, basePath)
+	}))
+
+	// Inventory: move an item up (delta<0) or down (delta>0)
+	r.Post("/api/item/{index}/move", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		deltaStr := r.URL.Query().Get("delta")
+		delta := 0
+		fmt.Sscanf(deltaStr, "%d", &delta)
+
+		if !store.MoveItem(charID, index, delta) {
+			return shared.Empty()
+		}
+		return inventoryPanel(store, charID, basePath)
+	}))
+
+	// Inventory: delete an item
+	r.Post("/api/item/{index}/delete", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		if !store.DeleteItem(charID, index) {
+			return shared.Empty()
+		}
+		return inventoryPanel(store, charID, basePath)
+	}))
+
+	// Finances: set cash, assets or the income override (empty income derives
+	// it from 信用, held within the occupation's 信用 range)
+	r.Post("/api/finances/{field}/set", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		// A rejected value is dropped; the re-render restores the stored one
+		store.SetFinanceField(charID, chi.URLParam(r, "field"), r.FormValue("value"))
+		return inventoryPanel(store, charID, basePath)
+	}))
 
 	// Memo update endpoint
 	r.Post("/api/memo/{id}/set", func(w http.ResponseWriter, r *http.Request) {
@@ -154,6 +569,16 @@ func Routes(store *Store) chi.Router {
 		return components.Cthulhu6SkillGrowUpdateFragments(pc, templSkill)
 	}))
 
+	// Skill check
+	r.Post("/api/skill/{key}/check", html(func(r *http.Request) templ.Component {
+		key := chi.URLParam(r, "key")
+
+		if _, err := store.CheckSkill(charID, key, checkModifier(r)); err != nil {
+			return shared.Empty()
+		}
+		return components.Cthulhu6CheckHistory(cthulhu6.BuildCheckRolls(store.GetChecks(charID)), true)
+	}))
+
 	// Skill field adjustment (job, hobby, perm, temp)
 	r.Post("/api/skill/{key}/{field}/adjust", html(func(r *http.Request) templ.Component {
 		key := chi.URLParam(r, "key")
@@ -194,7 +619,9 @@ func Routes(store *Store) chi.Router {
 		templSkill := cthulhu6.BuildSkill(status, key, updatedSkill)
 		remaining := cthulhu6.BuildRemainingPoints(status, skills)
 
-		return components.Cthulhu6SkillUpdateFragments(templSkill, field, remaining, basePath)
+		warnings := cthulhu6.BuildWarnings(status, skills)
+
+		return components.Cthulhu6SkillUpdateFragments(templSkill, field, remaining, warnings, basePath)
 	}))
 
 	// Add genre to multi-skill
@@ -235,7 +662,7 @@ func Routes(store *Store) chi.Router {
 		status := store.GetStatus(charID)
 		skills := store.GetSkills(charID)
 		state := cthulhu6.BuildSheetState(pc, status, skills)
-		return components.Cthulhu6SkillsPanel(state, true)
+		return components.Cthulhu6SkillsPanelWithPoints(state)
 	}))
 
 	// Toggle grow flag for genre
@@ -257,6 +684,19 @@ func Routes(store *Store) chi.Router {
 		return components.Cthulhu6GenreGrowUpdateFragments(pc, key, index, skill.Multi.Genres[index].Grow)
 	}))
 
+	// Genre check
+	r.Post("/api/skill/{key}/genre/{index}/check", html(func(r *http.Request) templ.Component {
+		key := chi.URLParam(r, "key")
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		if _, err := store.CheckGenre(charID, key, index, checkModifier(r)); err != nil {
+			return shared.Empty()
+		}
+		return components.Cthulhu6CheckHistory(cthulhu6.BuildCheckRolls(store.GetChecks(charID)), true)
+	}))
+
 	// Update genre label
 	r.Post("/api/skill/{key}/genre/{index}/label", html(func(r *http.Request) templ.Component {
 		key := chi.URLParam(r, "key")
@@ -318,7 +758,7 @@ func Routes(store *Store) chi.Router {
 		status := store.GetStatus(charID)
 		skills := store.GetSkills(charID)
 		state := cthulhu6.BuildSheetState(pc, status, skills)
-		return components.Cthulhu6SkillsPanel(state, true)
+		return components.Cthulhu6SkillsPanelWithPoints(state)
 	}))
 
 	// Custom skill: add
@@ -346,7 +786,7 @@ func Routes(store *Store) chi.Router {
 		status := store.GetStatus(charID)
 		skills := store.GetSkills(charID)
 		state := cthulhu6.BuildSheetState(pc, status, skills)
-		return components.Cthulhu6SkillsPanel(state, true)
+		return components.Cthulhu6SkillsPanelWithPoints(state)
 	}))
 
 	// Custom skill: grow toggle
@@ -367,6 +807,18 @@ func Routes(store *Store) chi.Router {
 		return components.Cthulhu6CustomSkillGrowUpdateFragments(pc, index, cs.Grow)
 	}))
 
+	// Custom skill: check
+	r.Post("/api/skill/custom/{index}/check", html(func(r *http.Request) templ.Component {
+		indexStr := chi.URLParam(r, "index")
+		index := 0
+		fmt.Sscanf(indexStr, "%d", &index)
+
+		if _, err := store.CheckCustomSkill(charID, index, checkModifier(r)); err != nil {
+			return shared.Empty()
+		}
+		return components.Cthulhu6CheckHistory(cthulhu6.BuildCheckRolls(store.GetChecks(charID)), true)
+	}))
+
 	// Custom skill: name update
 	r.Post("/api/skill/custom/{index}/name", html(func(r *http.Request) templ.Component {
 		indexStr := chi.URLParam(r, "index")
@@ -434,7 +886,9 @@ func Routes(store *Store) chi.Router {
 			Grow:  updatedCs.Grow,
 		}
 
-		return components.Cthulhu6CustomSkillUpdateFragments(index, templCs, field, remaining, basePath)
+		warnings := cthulhu6.BuildWarnings(status, skills)
+
+		return components.Cthulhu6CustomSkillUpdateFragments(index, templCs, field, remaining, warnings, basePath)
 	}))
 
 	// Extra points adjustment
@@ -491,24 +945,130 @@ func Routes(store *Store) chi.Router {
 		delta := 0
 		fmt.Sscanf(deltaStr, "%d", &delta)
 
-		pc := buildPageContext(store, charID, basePath)
-		updated := store.UpdateVariableBase(charID, key, delta)
-		if updated == nil {
+		if _, ok := store.GetStatus(charID).Variables[key]; !ok {
 			return shared.Empty()
 		}
 
+		// Rejected adjustments still re-render so the input shows the stored value
+		pc := buildPageContext(store, charID, basePath)
+		changes, _ := store.UpdateVariableBase(charID, key, delta)
+
 		status := store.GetStatus(charID)
 		skills := store.GetSkills(charID)
 		state := cthulhu6.BuildSheetState(pc, status, skills)
+		return statusFragments(state, status, changes)
+	}))
 
-		switch key {
-		case "DEX", "EDU":
-			return components.Cthulhu6StatusPanelWithSkills(state)
-		case "INT":
-			return components.Cthulhu6StatusPanelWithPoints(state)
-		default:
-			return components.Cthulhu6StatusPanel(state, true)
+	// Chat palette for BCDice-based tools (ココフォリア, Udonarium)
+	r.Get("/api/export/palette", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, cthulhu6.ChatPalette(store.GetStatus(charID), store.GetSkills(charID), store.GetWeapons(charID)))
+	})
+
+	// ココフォリア character piece, pasted onto a board from the clipboard
+	r.Get("/api/export/ccfolia", func(w http.ResponseWriter, r *http.Request) {
+		status, skills := store.GetStatus(charID), store.GetSkills(charID)
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		url := fmt.Sprintf("%s://%s%s/", scheme, r.Host, basePath)
+		piece := cthulhu6.Ccfolia(pieceName(skills), store.GetMemo(charID, "public-memo"), url, status, skills, store.GetWeapons(charID))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(piece)
+	})
+
+	// Udonarium character zip: the piece XML and the pinned portrait
+	r.Get("/api/export/udonarium", func(w http.ResponseWriter, r *http.Request) {
+		status, skills := store.GetStatus(charID), store.GetSkills(charID)
+		image, err := store.GetPortrait(r.Context(), charID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var buf bytes.Buffer
+		if err := cthulhu6.WriteUdonariumZip(&buf, pieceName(skills), store.GetMemo(charID, "public-memo"), image, status, skills, store.GetWeapons(charID)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="cthulhu6-%s.zip"`, charID))
+		w.Write(buf.Bytes())
+	})
+
+	// Pinned portrait, shown in the image gallery and exported with the
+	// Udonarium zip
+	r.Get("/api/portrait", func(w http.ResponseWriter, r *http.Request) {
+		image, err := store.GetPortrait(r.Context(), charID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if image == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", http.DetectContentType(image))
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write(image)
+	})
+
+	// Upload an image as the pinned portrait
+	r.Post("/api/portrait", html(func(r *http.Request) templ.Component {
+		pc := buildPageContext(store, charID, basePath)
+		file, _, err := r.FormFile("image")
+		if err != nil {
+			return components.ImageGallery(withPortrait(r.Context(), store, charID, pc, ""), "画像を選択してください")
+		}
+		defer file.Close()
+
+		image, err := io.ReadAll(io.LimitReader(file, maxPortraitSize+1))
+		if err == nil {
+			err = store.SetPortrait(r.Context(), charID, image)
+		}
+		if err != nil {
+			return components.ImageGallery(withPortrait(r.Context(), store, charID, pc, ""), "アップロードできません: "+err.Error())
+		}
+		sum := sha256.Sum256(image)
+		return components.ImageGallery(withPortrait(r.Context(), store, charID, pc, hex.EncodeToString(sum[:4])), "")
+	}))
+
+	// Remove the pinned portrait
+	r.Post("/api/portrait/delete", html(func(r *http.Request) templ.Component {
+		pc := buildPageContext(store, charID, basePath)
+		if err := store.DeletePortrait(r.Context(), charID); err != nil {
+			return components.ImageGallery(withPortrait(r.Context(), store, charID, pc, ""), "削除できません: "+err.Error())
+		}
+		return components.ImageGallery(pc, "")
+	}))
+
+	// Printable A4 sheet
+	r.Get("/api/export/pdf", func(w http.ResponseWriter, r *http.Request) {
+		pc := buildPageContext(store, charID, basePath)
+		memos := sheetMemos(pc, r.URL.Query().Get("secret") == "1")
+		status, skills := store.GetStatus(charID), store.GetSkills(charID)
+		var buf bytes.Buffer
+		if err := cthulhu6.WriteSheetPDF(&buf, pieceName(skills), status, skills, memos); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="cthulhu6-%s.pdf"`, charID))
+		w.Write(buf.Bytes())
+	})
+
+	// Import a character from another service's JSON export or chat palette,
+	// replacing the sheet and reporting what could not be mapped
+	r.Post("/api/import", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		x, err := cthulhu6.ParseExternal([]byte(r.FormValue("source")))
+		if err != nil {
+			return pages.ExternalImportResult("読み込めません: "+err.Error(), "", nil)
+		}
+		if err := store.ImportExternal(charID, x); err != nil {
+			return pages.ExternalImportResult("読み込めません: "+err.Error(), "", nil)
 		}
+		return pages.ExternalImportResult("読み込みました", basePath+"/", x.Unmapped)
 	}))
 
 	return r
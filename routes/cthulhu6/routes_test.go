@@ -1,19 +1,30 @@
 package cthulhu6
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
 
+	"charaxiv/dice"
+	"charaxiv/routes/gamesystem"
+	"charaxiv/storage"
 	"charaxiv/storage/coalesce"
 	"charaxiv/systems/cthulhu6"
+	"charaxiv/templates/shared"
 )
 
 // RouteTest defines a test case for a route
@@ -21,10 +32,11 @@ type RouteTest struct {
 	Method       string
 	Route        string
 	Desc         string
-	TestURL      string       // Actual URL to test (with params filled in)
-	Form         url.Values   // Form data to send
-	Query        string       // Query string (e.g., "delta=1")
-	Setup        func(*Store) // Optional setup before test
+	TestURL      string                         // Actual URL to test (with params filled in)
+	Form         url.Values                     // Form data to send
+	Body         func() (*bytes.Buffer, string) // Multipart body and its content type
+	Query        string                         // Query string (e.g., "delta=1")
+	Setup        func(*Store)                   // Optional setup before test
 	WantCode     int
 	WantContains []string // Strings that should be in response body
 }
@@ -91,6 +103,256 @@ var routeTests = []RouteTest{
 		WantCode:     http.StatusOK,
 		WantContains: []string{"skills-panel"},
 	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/status/generation",
+		Desc:         "Switch stat generation mode",
+		TestURL:      "/cthulhu6/api/status/generation",
+		Form:         url.Values{"mode": {"pointbuy"}, "budget": {"80"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "status-generation-remaining"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/status/era",
+		Desc:         "Switch the era preset",
+		TestURL:      "/cthulhu6/api/status/era",
+		Form:         url.Values{"era": {"gaslight"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel", "馬車"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/status/rules",
+		Desc:         "Select a house-rule profile",
+		TestURL:      "/cthulhu6/api/status/rules",
+		Form:         url.Values{"profile": {"custom"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel", "status-rules-input"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/status/rules/formula",
+		Desc:         "Edit a custom rule formula",
+		TestURL:      "/cthulhu6/api/status/rules/formula",
+		Form:         url.Values{"group": {"computed"}, "key": {"職業P"}, "formula": {"EDU*15"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/status/random",
+		Desc:         "Roll all status variables",
+		TestURL:      "/cthulhu6/api/status/random",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel", "status-roll-log"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/status/{key}/reroll",
+		Desc:         "Reroll a single status variable",
+		TestURL:      "/cthulhu6/api/status/status-SIZ/reroll",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "2D6"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/status/{key}/check",
+		Desc:         "Characteristic check",
+		TestURL:      "/cthulhu6/api/status/status-POW/check",
+		Form:         url.Values{"modifier": {"-10"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"check-history", "hx-swap-oob", "POW×5", "(-10)"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/growth/resolve",
+		Desc:    "Resolve growth checks",
+		TestURL: "/cthulhu6/api/growth/resolve",
+		Setup: func(s *Store) {
+			skill, _ := s.GetSkill("demo", "目星")
+			skill.Single.Grow = true
+			s.UpdateSkill("demo", "目星", skill)
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "growth-panel", "目星"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/sanity/check",
+		Desc:         "SAN check",
+		TestURL:      "/cthulhu6/api/sanity/check",
+		Form:         url.Values{"expr": {"1/1D6"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "SANチェック履歴", "1/1D6"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/sanity/session/reset",
+		Desc:    "Reset SAN loss session",
+		TestURL: "/cthulhu6/api/sanity/session/reset",
+		Setup: func(s *Store) {
+			s.SanCheck("demo", "1/1")
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "今回の減少"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/sanity/flag/{kind}/toggle",
+		Desc:         "Toggle temporary insanity",
+		TestURL:      "/cthulhu6/api/sanity/flag/temporary/toggle",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "sanity-flag--active"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/sanity/insanity/add",
+		Desc:         "Add insanity",
+		TestURL:      "/cthulhu6/api/sanity/insanity/add",
+		Form:         url.Values{"kind": {"phobia"}, "label": {"閉所恐怖症"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "閉所恐怖症", "恐怖症"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/sanity/insanity/{index}/delete",
+		Desc:    "Delete insanity",
+		TestURL: "/cthulhu6/api/sanity/insanity/0/delete",
+		Setup: func(s *Store) {
+			s.AddInsanity("demo", "閉所恐怖症", cthulhu6.InsanityPhobia)
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/occupation",
+		Desc:         "Select occupation",
+		TestURL:      "/cthulhu6/api/occupation",
+		Form:         url.Values{"id": {"private-eye"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"occupation-panel", "warnings-panel", "私立探偵", "次から1個選択"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/occupation/choices",
+		Desc:    "Pick occupation choice skills",
+		TestURL: "/cthulhu6/api/occupation/choices",
+		Setup: func(s *Store) {
+			s.SetOccupation("demo", "private-eye")
+		},
+		Form:         url.Values{"choice": {"0:0"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"occupation-panel", "checked"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/weapon/add",
+		Desc:         "Add weapon from catalog",
+		TestURL:      "/cthulhu6/api/weapon/add",
+		Form:         url.Values{"catalog": {"38-revolver"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"weapons-panel", ".38リボルバー", "拳銃"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/weapon/{index}/{field}/set",
+		Desc:    "Set weapon field",
+		TestURL: "/cthulhu6/api/weapon/0/damage/set",
+		Setup: func(s *Store) {
+			s.AddWeapon("demo", "")
+		},
+		Form:         url.Values{"value": {"1D6+1"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"weapons-panel", "1D6+1"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/weapon/{index}/delete",
+		Desc:    "Delete weapon",
+		TestURL: "/cthulhu6/api/weapon/0/delete",
+		Setup: func(s *Store) {
+			s.AddWeapon("demo", "fist")
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"weapons-panel", "武器はありません"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/weapon/{index}/check",
+		Desc:    "Weapon attack check",
+		TestURL: "/cthulhu6/api/weapon/0/check",
+		Setup: func(s *Store) {
+			s.AddWeapon("demo", "38-revolver")
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"check-history", "拳銃(.38リボルバー)"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/weapon/{index}/damage",
+		Desc:    "Weapon damage roll",
+		TestURL: "/cthulhu6/api/weapon/0/damage",
+		Setup: func(s *Store) {
+			s.AddWeapon("demo", "fist")
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"weapons-panel", "ダメージ履歴", "1D3["},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/item/add",
+		Desc:         "Add inventory item",
+		TestURL:      "/cthulhu6/api/item/add",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"inventory-panel", "/cthulhu6/api/item/0/name/set"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/item/{index}/{field}/set",
+		Desc:    "Set inventory item field",
+		TestURL: "/cthulhu6/api/item/0/name/set",
+		Setup: func(s *Store) {
+			s.AddItem("demo")
+		},
+		Form:         url.Values{"value": {"懐中電灯"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"inventory-panel", "懐中電灯"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/item/{index}/move",
+		Desc:    "Move inventory item",
+		TestURL: "/cthulhu6/api/item/0/move",
+		Query:   "delta=1",
+		Setup: func(s *Store) {
+			s.AddItem("demo")
+			s.AddItem("demo")
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"inventory-panel"},
+	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/item/{index}/delete",
+		Desc:    "Delete inventory item",
+		TestURL: "/cthulhu6/api/item/0/delete",
+		Setup: func(s *Store) {
+			s.AddItem("demo")
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"inventory-panel", "所持品はありません"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/finances/{field}/set",
+		Desc:         "Set finances field",
+		TestURL:      "/cthulhu6/api/finances/cash/set",
+		Form:         url.Values{"value": {"1200"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"inventory-panel", `value="1200"`},
+	},
 	{
 		Method:       "POST",
 		Route:        "/cthulhu6/api/memo/{id}/set",
@@ -108,6 +370,14 @@ var routeTests = []RouteTest{
 		WantCode:     http.StatusOK,
 		WantContains: []string{"hx-swap-oob", "skill-grow-回避"},
 	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/skill/{key}/check",
+		Desc:         "Skill check",
+		TestURL:      "/cthulhu6/api/skill/目星/check",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"check-history", "目星", "≦25"},
+	},
 	{
 		Method:       "POST",
 		Route:        "/cthulhu6/api/skill/{key}/{field}/adjust",
@@ -152,6 +422,14 @@ var routeTests = []RouteTest{
 		WantCode:     http.StatusOK,
 		WantContains: []string{"hx-swap-oob", "genre-grow-芸術-0"},
 	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/skill/{key}/genre/{index}/check",
+		Desc:         "Genre check",
+		TestURL:      "/cthulhu6/api/skill/母国語/genre/0/check",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"check-history", "母国語", "≦70"},
+	},
 	{
 		Method:  "POST",
 		Route:   "/cthulhu6/api/skill/{key}/genre/{index}/label",
@@ -211,6 +489,17 @@ var routeTests = []RouteTest{
 		WantCode:     http.StatusOK,
 		WantContains: []string{"hx-swap-oob", "skill-grow-custom-0"},
 	},
+	{
+		Method:  "POST",
+		Route:   "/cthulhu6/api/skill/custom/{index}/check",
+		Desc:    "Custom skill check",
+		TestURL: "/cthulhu6/api/skill/custom/0/check",
+		Setup: func(s *Store) {
+			s.AddCustomSkill("demo")
+		},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"check-history", "無名"},
+	},
 	{
 		Method:  "POST",
 		Route:   "/cthulhu6/api/skill/custom/{index}/name",
@@ -235,6 +524,99 @@ var routeTests = []RouteTest{
 		WantCode:     http.StatusOK,
 		WantContains: []string{"hx-swap-oob", "custom-0-job"},
 	},
+	{
+		Method:       "GET",
+		Route:        "/cthulhu6/api/export/palette",
+		Desc:         "Export chat palette",
+		TestURL:      "/cthulhu6/api/export/palette",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"CCB<={SAN} 【正気度ロール】", "CCB<=25 【目星】", "text/plain"},
+	},
+	{
+		Method:       "GET",
+		Route:        "/cthulhu6/api/export/ccfolia",
+		Desc:         "Export ココフォリア piece",
+		TestURL:      "/cthulhu6/api/export/ccfolia",
+		WantCode:     http.StatusOK,
+		WantContains: []string{`"kind":"character"`, `"label":"SAN","value":55,"max":99`, `"externalUrl":"http://example.com/cthulhu6/"`, "application/json"},
+	},
+	{
+		Method:       "GET",
+		Route:        "/cthulhu6/api/portrait",
+		Desc:         "Pinned portrait",
+		TestURL:      "/cthulhu6/api/portrait",
+		Setup:        pinTestPortrait,
+		WantCode:     http.StatusOK,
+		WantContains: []string{"image/png"},
+	},
+	{
+		Method:   "GET",
+		Route:    "/cthulhu6/api/portrait",
+		Desc:     "Pinned portrait (none)",
+		TestURL:  "/cthulhu6/api/portrait",
+		WantCode: http.StatusNotFound,
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/portrait",
+		Desc:         "Upload portrait",
+		TestURL:      "/cthulhu6/api/portrait",
+		Body:         multipartImage(testPortrait),
+		WantCode:     http.StatusOK,
+		WantContains: []string{`id="image-gallery"`, `src="/cthulhu6/api/portrait?v=`, "download"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/portrait",
+		Desc:         "Upload portrait (not an image)",
+		TestURL:      "/cthulhu6/api/portrait",
+		Body:         multipartImage([]byte("not an image")),
+		WantCode:     http.StatusOK,
+		WantContains: []string{"image-message", "アップロードできません", "image-placeholder"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/portrait/delete",
+		Desc:         "Delete portrait",
+		TestURL:      "/cthulhu6/api/portrait/delete",
+		Setup:        pinTestPortrait,
+		WantCode:     http.StatusOK,
+		WantContains: []string{`id="image-gallery"`, "image-placeholder"},
+	},
+	{
+		Method:       "GET",
+		Route:        "/cthulhu6/api/export/udonarium",
+		Desc:         "Export Udonarium zip",
+		TestURL:      "/cthulhu6/api/export/udonarium",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"application/zip", "data.xml"},
+	},
+	{
+		Method:       "GET",
+		Route:        "/cthulhu6/api/export/pdf",
+		Desc:         "Export printable PDF",
+		TestURL:      "/cthulhu6/api/export/pdf?secret=1",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"application/pdf", "%PDF-"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/import",
+		Desc:         "Import chat palette",
+		TestURL:      "/cthulhu6/api/import",
+		Form:         url.Values{"source": {"CCB<=80 【目星】\n1d3 【こぶし ダメージ】"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"external-import-result", "読み込みました", "/cthulhu6/", "こぶし ダメージ"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu6/api/import",
+		Desc:         "Import unknown JSON",
+		TestURL:      "/cthulhu6/api/import",
+		Form:         url.Values{"source": {`{"foo":1}`}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"external-import-result", "unknown JSON format"},
+	},
 }
 
 // TestRoutes runs all route tests from the table
@@ -257,7 +639,11 @@ func TestRoutes(t *testing.T) {
 
 			// Build request
 			var req *http.Request
-			if tt.Form != nil {
+			if tt.Body != nil {
+				body, contentType := tt.Body()
+				req = httptest.NewRequest(tt.Method, testURL, body)
+				req.Header.Set("Content-Type", contentType)
+			} else if tt.Form != nil {
 				req = httptest.NewRequest(tt.Method, testURL, strings.NewReader(tt.Form.Encode()))
 				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 			} else {
@@ -340,9 +726,15 @@ func setupTestRouter(t *testing.T) (chi.Router, *Store, func()) {
 		t.Fatalf("Failed to create temp dir: %v", err)
 	}
 
+	backend, err := coalesce.NewDiskBackend(filepath.Join(tmpDir, "characters"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create disk backend: %v", err)
+	}
+
 	cs, err := coalesce.New(coalesce.Config{
 		DBPath:  filepath.Join(tmpDir, "buffer.db"),
-		DataDir: filepath.Join(tmpDir, "characters"),
+		Backend: backend,
 	})
 	if err != nil {
 		os.RemoveAll(tmpDir)
@@ -350,6 +742,7 @@ func setupTestRouter(t *testing.T) (chi.Router, *Store, func()) {
 	}
 
 	store := NewStore(cs)
+	store.images = storage.NewMemoryStorage()
 	r := chi.NewRouter()
 	r.Mount("/cthulhu6", Routes(store))
 
@@ -521,3 +914,803 @@ func TestINTChangeIncludesPointsDisplay(t *testing.T) {
 		t.Error("INT change should include points display in OOB response")
 	}
 }
+
+// TestStatusRandomRollsAllVariables tests that rolling writes every base within range and logs each roll
+func TestStatusRandomRollsAllVariables(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	store.roller = dice.Seeded(42)
+
+	req := httptest.NewRequest("POST", "/cthulhu6/api/status/random", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	status := store.GetStatus("demo")
+	if len(status.Rolls) != len(cthulhu6.VariableOrder) {
+		t.Fatalf("Expected %d logged rolls, got %d", len(cthulhu6.VariableOrder), len(status.Rolls))
+	}
+	for i, roll := range status.Rolls {
+		key := cthulhu6.VariableOrder[i]
+		v := status.Variables[key]
+		if roll.Key != key {
+			t.Errorf("Roll %d: expected key %s, got %s", i, key, roll.Key)
+		}
+		if v.Base != roll.Value {
+			t.Errorf("%s: base %d does not match rolled value %d", key, v.Base, roll.Value)
+		}
+		if v.Base < v.Min || v.Base > v.Max {
+			t.Errorf("%s: base %d out of range [%d, %d]", key, v.Base, v.Min, v.Max)
+		}
+	}
+}
+
+// TestStatusRerollSingleVariable tests that rerolling changes only one variable and appends to the log
+func TestStatusRerollSingleVariable(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	// Always rolls 1s, so 3D6 gives the minimum
+	store.roller = dice.NewRoller(constSource(0))
+
+	before := store.GetStatus("demo")
+
+	req := httptest.NewRequest("POST", "/cthulhu6/api/status/status-STR/reroll", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	after := store.GetStatus("demo")
+	if after.Variables["STR"].Base != 3 {
+		t.Errorf("Expected STR to be 3, got %d", after.Variables["STR"].Base)
+	}
+	for _, key := range cthulhu6.VariableOrder {
+		if key != "STR" && after.Variables[key].Base != before.Variables[key].Base {
+			t.Errorf("%s changed from %d to %d", key, before.Variables[key].Base, after.Variables[key].Base)
+		}
+	}
+	if len(after.Rolls) != 1 || after.Rolls[0].Detail != "3D6[1,1,1]" {
+		t.Errorf("Expected one logged roll 3D6[1,1,1], got %+v", after.Rolls)
+	}
+}
+
+// TestStatusRerollUnknownVariable tests that unknown keys leave the character untouched
+func TestStatusRerollUnknownVariable(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	req := httptest.NewRequest("POST", "/cthulhu6/api/status/LUCK/reroll", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if len(store.GetStatus("demo").Rolls) != 0 {
+		t.Error("Expected no rolls for unknown variable")
+	}
+}
+
+// TestPointBuyEnforcesBudget tests that bases can't exceed the point-buy budget
+func TestPointBuyEnforcesBudget(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	// Defaults total 95, so an 80 point budget resets every base to its Min (total 37)
+	if err := store.SetGeneration("demo", cthulhu6.Generation{Mode: cthulhu6.GenerationPointBuy, Budget: 80}); err != nil {
+		t.Fatalf("SetGeneration: %v", err)
+	}
+	if used := store.GetStatus("demo").PointsUsed(); used != 37 {
+		t.Fatalf("Expected bases reset to minimums (37), got %d", used)
+	}
+
+	for _, key := range []string{"STR", "CON", "POW"} {
+		form := url.Values{}
+		form.Set("status_"+key, "18")
+		req := httptest.NewRequest("POST", "/cthulhu6/api/status/status-"+key+"/set", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	status := store.GetStatus("demo")
+	if used := status.PointsUsed(); used != 80 {
+		t.Errorf("Expected exactly the 80 point budget to be spent, got %d", used)
+	}
+	if pow := status.Variables["POW"].Base; pow != 16 {
+		t.Errorf("Expected POW clamped to remaining budget (16), got %d", pow)
+	}
+	if err := status.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	// Adjusting past the budget leaves the base unchanged
+	req := httptest.NewRequest("POST", "/cthulhu6/api/status/status-DEX/adjust?delta=1", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if dex := store.GetStatus("demo").Variables["DEX"].Base; dex != 3 {
+		t.Errorf("Expected DEX to stay 3, got %d", dex)
+	}
+
+	// Rolling is disabled
+	if _, err := store.RollVariables("demo"); err == nil {
+		t.Error("Expected rolling to be rejected in point-buy mode")
+	}
+}
+
+// TestArraySwapsValues tests that array mode swaps values instead of allowing arbitrary bases
+func TestArraySwapsValues(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	if err := store.SetGeneration("demo", cthulhu6.Generation{Mode: cthulhu6.GenerationArray}); err != nil {
+		t.Fatalf("SetGeneration: %v", err)
+	}
+	status := store.GetStatus("demo")
+	if status.Variables["STR"].Base != 15 || status.Variables["EDU"].Base != 8 {
+		t.Fatalf("Expected the default array in display order, got STR=%d EDU=%d",
+			status.Variables["STR"].Base, status.Variables["EDU"].Base)
+	}
+
+	// Setting STR to EDU's value swaps them
+	form := url.Values{}
+	form.Set("status_STR", "8")
+	req := httptest.NewRequest("POST", "/cthulhu6/api/status/status-STR/set", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	status = store.GetStatus("demo")
+	if status.Variables["STR"].Base != 8 || status.Variables["EDU"].Base != 15 {
+		t.Errorf("Expected STR=8 EDU=15, got STR=%d EDU=%d", status.Variables["STR"].Base, status.Variables["EDU"].Base)
+	}
+	// EDU changed, so the skills panel must be refreshed too
+	if !strings.Contains(w.Body.String(), "skills-panel") {
+		t.Error("Expected swapped EDU to include skills panel")
+	}
+
+	// Values outside the array are rejected
+	if _, err := store.SetVariableBase("demo", "STR", 16); err == nil {
+		t.Error("Expected value outside the array to be rejected")
+	}
+
+	// Adjust steps to the next array value
+	req = httptest.NewRequest("POST", "/cthulhu6/api/status/status-STR/adjust?delta=1", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	status = store.GetStatus("demo")
+	if status.Variables["STR"].Base != 9 || status.Variables["INT"].Base != 8 {
+		t.Errorf("Expected STR=9 INT=8, got STR=%d INT=%d", status.Variables["STR"].Base, status.Variables["INT"].Base)
+	}
+	if err := status.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+// TestRoll4D6DropsLowest tests that 4d6 mode rolls four dice for 3D6 variables
+func TestRoll4D6DropsLowest(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	store.roller = dice.NewRoller(constSource(0))
+
+	if err := store.SetGeneration("demo", cthulhu6.Generation{Mode: cthulhu6.GenerationRoll4D6}); err != nil {
+		t.Fatalf("SetGeneration: %v", err)
+	}
+	rolls, err := store.RollVariables("demo", "STR", "EDU")
+	if err != nil {
+		t.Fatalf("RollVariables: %v", err)
+	}
+	if rolls[0].Detail != "4D6DL1[(1),1,1,1]" {
+		t.Errorf("Expected STR to roll 4D6DL1, got %s", rolls[0].Detail)
+	}
+	if rolls[1].Detail != "4D6DL1[(1),1,1,1]+3" {
+		t.Errorf("Expected EDU to roll 4D6DL1+3, got %s", rolls[1].Detail)
+	}
+}
+
+// TestSkillCheckRecordsHistory tests that checks resolve success levels and append to history
+func TestSkillCheckRecordsHistory(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	// 1D100 always rolls 20
+	store.roller = dice.NewRoller(constSource(19))
+
+	// 目星 is 25: 20 is a success, and a special once the target reaches 100
+	for _, modifier := range []string{"0", "75", "-10"} {
+		req := httptest.NewRequest("POST", "/cthulhu6/api/skill/目星/check?modifier="+modifier, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	checks := store.GetChecks("demo")
+	if len(checks) != 3 {
+		t.Fatalf("Expected 3 checks, got %d", len(checks))
+	}
+	want := []cthulhu6.SuccessLevel{cthulhu6.LevelSuccess, cthulhu6.LevelSpecial, cthulhu6.LevelFailure}
+	for i, c := range checks {
+		if c.Roll != 20 || c.Level != want[i] {
+			t.Errorf("Check %d: got roll %d level %s, want 20 %s", i, c.Roll, c.Level, want[i])
+		}
+	}
+
+	// Unknown skills don't add history
+	req := httptest.NewRequest("POST", "/cthulhu6/api/skill/nonexistent/check", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if len(store.GetChecks("demo")) != 3 {
+		t.Error("Expected unknown skill check to be ignored")
+	}
+}
+
+// TestResolveGrowth tests that flagged skills grow, flags clear and a report is recorded
+func TestResolveGrowth(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	// Every die rolls its maximum: 1D100 = 100 always succeeds, 1D10 = 10
+	store.roller = dice.NewRoller(maxSource{})
+
+	skill, _ := store.GetSkill("demo", "回避")
+	skill.Single.Grow = true
+	store.UpdateSkill("demo", "回避", skill)
+	genre, _ := store.GetSkill("demo", "母国語")
+	genre.Multi.Genres[0].Grow = true
+	store.UpdateSkill("demo", "母国語", genre)
+	store.AddCustomSkill("demo")
+	cs, _ := store.GetCustomSkill("demo", 0)
+	cs.Grow = true
+	store.UpdateCustomSkill("demo", 0, cs)
+
+	req := httptest.NewRequest("POST", "/cthulhu6/api/growth/resolve", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	skill, _ = store.GetSkill("demo", "回避")
+	if skill.Single.Perm != 10 || skill.Single.Grow {
+		t.Errorf("回避: expected perm 10 and grow cleared, got perm %d grow %v", skill.Single.Perm, skill.Single.Grow)
+	}
+	genre, _ = store.GetSkill("demo", "母国語")
+	if g := genre.Multi.Genres[0]; g.Perm != 10 || g.Grow {
+		t.Errorf("母国語: expected perm 10 and grow cleared, got perm %d grow %v", g.Perm, g.Grow)
+	}
+	cs, _ = store.GetCustomSkill("demo", 0)
+	if cs.Perm != 10 || cs.Grow {
+		t.Errorf("custom: expected perm 10 and grow cleared, got perm %d grow %v", cs.Perm, cs.Grow)
+	}
+
+	reports := store.GetGrowthReports("demo")
+	if len(reports) != 1 || len(reports[0].Results) != 3 || reports[0].Gained() != 3 {
+		t.Fatalf("Expected one report with 3 gains, got %+v", reports)
+	}
+	if res := reports[0].Results[0]; res.Label != "回避" || res.Before != 22 || res.After() != 32 {
+		t.Errorf("Unexpected first result %+v", res)
+	}
+
+	// Nothing flagged: no new report
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/cthulhu6/api/growth/resolve", nil))
+	if len(store.GetGrowthReports("demo")) != 1 {
+		t.Error("Expected no report when no skills are marked")
+	}
+}
+
+// TestResolveGrowthFailure tests that rolls at or under the total don't grow but still clear the flag
+func TestResolveGrowthFailure(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	store.roller = dice.NewRoller(constSource(0))
+
+	skill, _ := store.GetSkill("demo", "目星")
+	skill.Single.Grow = true
+	store.UpdateSkill("demo", "目星", skill)
+
+	report, err := store.ResolveGrowth("demo")
+	if err != nil {
+		t.Fatalf("ResolveGrowth: %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].Success {
+		t.Fatalf("Expected one failed result, got %+v", report.Results)
+	}
+	skill, _ = store.GetSkill("demo", "目星")
+	if skill.Single.Perm != 0 || skill.Single.Grow {
+		t.Errorf("Expected perm 0 and grow cleared, got perm %d grow %v", skill.Single.Perm, skill.Single.Grow)
+	}
+}
+
+// TestSanCheckTracksInsanity tests SAN loss, session tracking and insanity flags
+func TestSanCheckTracksInsanity(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	// 1D100 = 100 always fails; 1D6 = 6
+	store.roller = dice.NewRoller(maxSource{})
+
+	san := store.GetStatus("demo").EffectiveParameter("SAN") // POW 11 → 55
+
+	form := url.Values{"expr": {"1/1D6"}}
+	req := httptest.NewRequest("POST", "/cthulhu6/api/sanity/check", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	status := store.GetStatus("demo")
+	if got := status.EffectiveParameter("SAN"); got != san-6 {
+		t.Fatalf("Expected SAN %d, got %d", san-6, got)
+	}
+	sanity := status.Sanity
+	if !sanity.Temporary || sanity.Indefinite {
+		t.Errorf("Expected temporary insanity only, got temporary=%v indefinite=%v", sanity.Temporary, sanity.Indefinite)
+	}
+	if sanity.SessionStart == nil || *sanity.SessionStart != san || sanity.SessionLoss != 6 {
+		t.Errorf("Unexpected session tracking %+v", sanity)
+	}
+
+	// 55/5 = 11: the second loss of 6 reaches the indefinite threshold
+	if _, err := store.SanCheck("demo", "1/1D6"); err != nil {
+		t.Fatalf("SanCheck: %v", err)
+	}
+	sanity = store.GetStatus("demo").Sanity
+	if !sanity.Indefinite || !sanity.Checks[1].Indefinite || len(sanity.Checks) != 2 {
+		t.Errorf("Expected indefinite insanity after losing 12 of 55, got %+v", sanity)
+	}
+
+	// Resetting the session keeps the flags
+	if err := store.ResetSanSession("demo"); err != nil {
+		t.Fatalf("ResetSanSession: %v", err)
+	}
+	sanity = store.GetStatus("demo").Sanity
+	if sanity.SessionStart != nil || sanity.SessionLoss != 0 || !sanity.Indefinite {
+		t.Errorf("Expected session cleared with flags kept, got %+v", sanity)
+	}
+}
+
+// TestSanCheckInvalidExpression tests that malformed loss expressions change nothing
+func TestSanCheckInvalidExpression(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	for _, expr := range []string{"", "1D6", "1/1D6/2", "1/abc", "1/1D6<=3"} {
+		if _, err := store.SanCheck("demo", expr); err == nil {
+			t.Errorf("Expected %q to be rejected", expr)
+		}
+	}
+	if len(store.GetStatus("demo").Sanity.Checks) != 0 {
+		t.Error("Expected no checks recorded")
+	}
+}
+
+// TestSkillAdjustUpdatesWarnings tests that allocation warnings follow skill changes
+func TestSkillAdjustUpdatesWarnings(t *testing.T) {
+	r, _, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/cthulhu6/", nil))
+	if !strings.Contains(rec.Body.String(), "問題はありません") {
+		t.Error("Expected no warnings on a new sheet")
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("POST", "/cthulhu6/api/skill/クトゥルフ神話/hobby/adjust?delta=1", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, `id="warnings-panel"`) || !strings.Contains(body, "クトゥルフ神話に職業P・興味Pは割り振れません") {
+		t.Errorf("Expected mythos warning in response, got %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("POST", "/cthulhu6/api/skill/クトゥルフ神話/hobby/adjust?delta=-1", nil))
+	if !strings.Contains(rec.Body.String(), "問題はありません") {
+		t.Error("Expected warning cleared after removing the points")
+	}
+}
+
+// TestOccupationSelection tests occupation selection, picks and the resulting warnings
+func TestOccupationSelection(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	if err := store.SetOccupation("demo", "unknown"); err == nil {
+		t.Error("Expected unknown occupation to be rejected")
+	}
+	if err := store.SetOccupationChoices("demo", map[int][]int{0: {0}}); err == nil {
+		t.Error("Expected picks without an occupation to be rejected")
+	}
+
+	if err := store.SetOccupation("demo", "professor"); err != nil {
+		t.Fatalf("SetOccupation: %v", err)
+	}
+	// Choose 3: out-of-range and extra picks are dropped, unknown slots ignored
+	if err := store.SetOccupationChoices("demo", map[int][]int{0: {4, 2, 99, 0, 1}, 3: {0}}); err != nil {
+		t.Fatalf("SetOccupationChoices: %v", err)
+	}
+	sel := store.GetSkills("demo").Occupation
+	if sel.ID != "professor" || len(sel.Choices) != 1 || len(sel.Choices[0]) != 3 {
+		t.Fatalf("Unexpected selection %+v", sel)
+	}
+
+	skill, _ := store.GetSkill("demo", "目星")
+	skill.Single.Job = 10
+	store.UpdateSkill("demo", "目星", skill)
+	found := false
+	for _, w := range cthulhu6.ValidateSheet(store.GetStatus("demo"), store.GetSkills("demo")) {
+		found = found || (w.Kind == cthulhu6.WarningNotOccupation && w.Subject == "目星")
+	}
+	if !found {
+		t.Error("Expected 目星 to be flagged for a professor")
+	}
+
+	// Changing the occupation clears the picks; an empty id clears the occupation
+	store.SetOccupation("demo", "soldier")
+	if sel := store.GetSkills("demo").Occupation; len(sel.Choices[0]) != 0 {
+		t.Errorf("Expected picks cleared, got %+v", sel)
+	}
+	store.SetOccupation("demo", "")
+	if sel := store.GetSkills("demo").Occupation; sel.ID != "" {
+		t.Errorf("Expected occupation cleared, got %+v", sel)
+	}
+}
+
+// TestWeaponDamageAddsBonus tests that melee damage includes the damage bonus and ranged does not
+func TestWeaponDamageAddsBonus(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	store.roller = dice.NewRoller(maxSource{})
+	store.SetDamageBonus("demo", "+1d4")
+
+	store.AddWeapon("demo", "fist")
+	store.AddWeapon("demo", "38-revolver")
+
+	melee, err := store.RollWeaponDamage("demo", 0)
+	if err != nil {
+		t.Fatalf("RollWeaponDamage: %v", err)
+	}
+	if melee.Total != 3+4 {
+		t.Errorf("Expected fist damage 1D3+1D4 = 7, got %d (%s)", melee.Total, melee.Detail)
+	}
+	ranged, _ := store.RollWeaponDamage("demo", 1)
+	if ranged.Total != 10 {
+		t.Errorf("Expected revolver damage 1D10 = 10, got %d (%s)", ranged.Total, ranged.Detail)
+	}
+	if got := len(store.GetDamageRolls("demo")); got != 2 {
+		t.Errorf("Expected 2 damage rolls recorded, got %d", got)
+	}
+}
+
+// TestWeaponFieldValidation tests that invalid weapon fields are rejected
+func TestWeaponFieldValidation(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	store.AddWeapon("demo", "fist")
+
+	for field, value := range map[string]string{
+		"damage":      "1D6>=3",
+		"ammo":        "-1",
+		"malfunction": "abc",
+		"color":       "red",
+	} {
+		if err := store.SetWeaponField("demo", 0, field, value); err == nil {
+			t.Errorf("Expected %s=%q to be rejected", field, value)
+		}
+	}
+	if err := store.SetWeaponField("demo", 5, "name", "x"); err == nil {
+		t.Error("Expected missing weapon to be rejected")
+	}
+	if err := store.AddWeapon("demo", "unknown"); err == nil {
+		t.Error("Expected unknown catalog weapon to be rejected")
+	}
+	if w := store.GetWeapons("demo")[0]; w.Damage != "1D3" || w.Ammo != 0 {
+		t.Errorf("Expected weapon unchanged, got %+v", w)
+	}
+}
+
+// TestInventoryReorder tests item field updates and reordering
+func TestInventoryReorder(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	for _, name := range []string{"ナイフ", "ロープ", "懐中電灯"} {
+		store.AddItem("demo")
+		items := store.GetInventory("demo")
+		if err := store.SetItemField("demo", len(items)-1, "name", name); err != nil {
+			t.Fatalf("SetItemField: %v", err)
+		}
+	}
+	if err := store.SetItemField("demo", 0, "quantity", "-2"); err == nil {
+		t.Error("Expected negative quantity to be rejected")
+	}
+	store.SetItemField("demo", 1, "stored", "true")
+
+	if !store.MoveItem("demo", 2, -2) {
+		t.Fatal("Expected item to move")
+	}
+	if store.MoveItem("demo", 0, -1) {
+		t.Error("Expected no move past the top")
+	}
+	items := store.GetInventory("demo")
+	got := []string{items[0].Name, items[1].Name, items[2].Name}
+	if got[0] != "懐中電灯" || got[1] != "ナイフ" || got[2] != "ロープ" || !items[2].Stored || items[1].Quantity != 1 {
+		t.Errorf("Unexpected inventory %+v", items)
+	}
+}
+
+// TestFinancesIncome tests that income is derived from 信用 and the
+// occupation's 信用 range until overridden
+func TestFinancesIncome(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	status, skills := store.GetStatus("demo"), store.GetSkills("demo")
+	inv := cthulhu6.BuildInventory(status, skills, nil, store.GetFinances("demo"))
+	if !inv.IncomeDerived || inv.Income != cthulhu6.DerivedIncome(inv.Credit) {
+		t.Errorf("Expected income derived from 信用 %d, got %+v", inv.Credit, inv)
+	}
+
+	if err := store.SetOccupation("demo", "dilettante"); err != nil {
+		t.Fatalf("SetOccupation: %v", err)
+	}
+	status, skills = store.GetStatus("demo"), store.GetSkills("demo")
+	inv = cthulhu6.BuildInventory(status, skills, nil, store.GetFinances("demo"))
+	if inv.Credit != 50 || inv.Income != cthulhu6.DerivedIncome(50) {
+		t.Errorf("Expected income derived from the dilettante's minimum 信用 50, got %+v", inv)
+	}
+
+	if err := store.SetFinanceField("demo", "income", "5000"); err != nil {
+		t.Fatalf("SetFinanceField: %v", err)
+	}
+	inv = cthulhu6.BuildInventory(status, skills, nil, store.GetFinances("demo"))
+	if inv.IncomeDerived || inv.Income != 5000 {
+		t.Errorf("Expected income override 5000, got %+v", inv)
+	}
+}
+
+// TestRuleProfiles tests that profiles change derived values and that formulas
+// can only be edited under the custom profile
+func TestRuleProfiles(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	post := func(path string, form url.Values) string {
+		req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	status := store.GetStatus("demo")
+	edu, app := status.Variables["EDU"].Sum(), status.Variables["APP"].Sum()
+
+	post("/cthulhu6/api/status/rules", url.Values{"profile": {"edu-app"}})
+	status = store.GetStatus("demo")
+	if got, want := status.ComputedValues()["職業P"], edu*10+app*10; got != want {
+		t.Errorf("Expected 職業P %d under edu-app, got %d", want, got)
+	}
+	if !status.PointsDependOn("APP") {
+		t.Error("APP should affect skill points under edu-app")
+	}
+
+	// Formulas are rejected outside the custom profile
+	if err := store.SetRuleFormula("demo", "computed", "職業P", "EDU*5"); err == nil {
+		t.Error("Expected formula edit to fail outside custom profile")
+	}
+
+	// Switching to custom starts from the current profile's formulas
+	post("/cthulhu6/api/status/rules", url.Values{"profile": {"custom"}})
+	if got, want := store.GetStatus("demo").ComputedValues()["職業P"], edu*10+app*10; got != want {
+		t.Errorf("Expected custom rules to copy edu-app 職業P %d, got %d", want, got)
+	}
+
+	post("/cthulhu6/api/status/rules/formula", url.Values{"group": {"skills"}, "key": {"回避"}, "formula": {"25"}})
+	if got := store.GetStatus("demo").SkillInitialValue("回避"); got != 25 {
+		t.Errorf("Expected 回避 initial 25, got %d", got)
+	}
+
+	// Invalid formulas leave the rules unchanged
+	post("/cthulhu6/api/status/rules/formula", url.Values{"group": {"computed"}, "key": {"職業P"}, "formula": {"1d6"}})
+	if got, want := store.GetStatus("demo").ComputedValues()["職業P"], edu*10+app*10; got != want {
+		t.Errorf("Expected rejected formula to keep 職業P %d, got %d", want, got)
+	}
+}
+
+// TestEraSwitchMigratesPoints tests that switching eras keeps allocated points
+// and that the loaded skill list follows the stored era
+func TestEraSwitchMigratesPoints(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	computer, _ := store.GetSkill("demo", "コンピューター")
+	computer.Single.Job = 30
+	store.UpdateSkill("demo", "コンピューター", computer)
+	if err := store.SetEra("demo", cthulhu6.Era1920s); err != nil {
+		t.Fatalf("SetEra: %v", err)
+	}
+	skills := store.GetSkills("demo")
+	if _, ok := skills.Categories[cthulhu6.SkillCategoryKnowledge].Skills["コンピューター"]; ok {
+		t.Error("Expected コンピューター removed in the 1920s")
+	}
+	if len(skills.Custom) != 1 || skills.Custom[0].Name != "コンピューター" || skills.Custom[0].Job != 30 {
+		t.Errorf("Expected コンピューター points kept as a custom skill, got %+v", skills.Custom)
+	}
+	if job, _ := store.GetStatus("demo").RemainingPoints(skills); job != 280-30 {
+		t.Errorf("Expected 250 職業P remaining, got %d", job)
+	}
+
+	if err := store.SetEra("demo", "future"); err == nil {
+		t.Error("Expected an unknown era to be rejected")
+	}
+}
+
+// TestImportExternalReplacesSheet tests that an imported Charaeno export replaces the sheet
+func TestImportExternalReplacesSheet(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	store.AddCustomSkill("demo")
+	source := `{"name":"探索者","variables":{"STR":12,"DEX":14,"HP":10},"skills":[{"name":"運転(二輪車)","value":60}],"note":"メモ"}`
+	form := url.Values{"source": {source}}
+	req := httptest.NewRequest("POST", "/cthulhu6/api/import", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	status := store.GetStatus("demo")
+	if got := status.Variables["DEX"].Base; got != 14 {
+		t.Errorf("Expected DEX 14, got %d", got)
+	}
+	skills := store.GetSkills("demo")
+	if len(skills.Custom) != 0 {
+		t.Errorf("Expected custom skills replaced, got %+v", skills.Custom)
+	}
+	drive := skills.Categories[cthulhu6.SkillCategoryAction].Skills["運転"].Multi.Genres
+	if g := drive[len(drive)-1]; g.Label != "二輪車" || g.Perm != 40 {
+		t.Errorf("Expected 運転(二輪車) with 40 points, got %+v", g)
+	}
+	if got := store.GetMemo("demo", "public-memo"); got != "メモ" {
+		t.Errorf("Expected imported memo, got %q", got)
+	}
+}
+
+// TestSystemExportImport tests that an exported character imports back and
+// malformed values are rejected
+func TestSystemExportImport(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	sys := &System{Documents: gamesystem.NewDocuments(store.coalesce, DefaultDocument, documentFields...), store: store}
+
+	store.roller = dice.Seeded(1)
+	store.RollVariables("demo")
+	store.CheckSkill("demo", "目星", 0)
+	store.AddWeapon("demo", "")
+	store.RollWeaponDamage("demo", 0)
+	store.AddItem("demo")
+	store.SetFinanceField("demo", "cash", "1200")
+	store.SetMemo("demo", "public-memo", "メモ")
+	data, err := sys.Export("demo")
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if err := sys.Import("copy", data); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if got := store.GetFinances("copy").Cash; got != 1200 {
+		t.Errorf("Expected imported cash 1200, got %d", got)
+	}
+	if len(store.GetChecks("copy")) != 1 || len(store.GetStatus("copy").Rolls) != len(cthulhu6.VariableOrder) {
+		t.Error("Expected the check history and roll log imported")
+	}
+
+	if err := sys.Import("copy", map[string]any{"status": "x"}); err == nil {
+		t.Error("Expected a malformed status to be rejected")
+	}
+	if err := sys.Import("copy", map[string]any{"finances": map[string]any{"cash": "lots"}}); err == nil {
+		t.Error("Expected malformed finances to be rejected")
+	}
+	if got := store.GetFinances("copy").Cash; got != 1200 {
+		t.Errorf("Expected a rejected import to leave the character unchanged, got cash %d", got)
+	}
+}
+
+// testPortrait is the smallest data detected as a PNG
+var testPortrait = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+
+// pinTestPortrait pins testPortrait to the demo character
+func pinTestPortrait(store *Store) {
+	store.SetPortrait(context.Background(), "demo", testPortrait)
+}
+
+// multipartImage builds a multipart body uploading image as "image"
+func multipartImage(image []byte) func() (*bytes.Buffer, string) {
+	return func() (*bytes.Buffer, string) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		fw, _ := mw.CreateFormFile("image", "portrait.png")
+		fw.Write(image)
+		mw.Close()
+		return &buf, mw.FormDataContentType()
+	}
+}
+
+func TestExportUdonariumIncludesPortrait(t *testing.T) {
+	r, _, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	body, contentType := multipartImage(testPortrait)()
+	req := httptest.NewRequest("POST", "/cthulhu6/api/portrait", body)
+	req.Header.Set("Content-Type", contentType)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/cthulhu6/api/export/udonarium", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("Read zip: %v", err)
+	}
+	sum := sha256.Sum256(testPortrait)
+	want := map[string]bool{"data.xml": false, hex.EncodeToString(sum[:]) + ".png": false}
+	for _, f := range zr.File {
+		want[f.Name] = true
+	}
+	for name, found := range want {
+		if !found {
+			t.Errorf("Expected %s in zip", name)
+		}
+	}
+}
+
+func TestSetPortraitRejects(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := store.SetPortrait(ctx, "demo", []byte("not an image")); err == nil {
+		t.Error("Expected a non-image to be rejected")
+	}
+	large := append(slices.Clone(testPortrait), make([]byte, maxPortraitSize)...)
+	if err := store.SetPortrait(ctx, "demo", large); err == nil {
+		t.Error("Expected an oversized image to be rejected")
+	}
+	if store.HasPortrait(ctx, "demo") {
+		t.Error("Expected rejected uploads to leave no portrait")
+	}
+
+	store.images = nil
+	if err := store.SetPortrait(ctx, "demo", testPortrait); err == nil {
+		t.Error("Expected an upload without image storage to fail")
+	}
+}
+
+func TestSheetMemosRespectSecretVisibility(t *testing.T) {
+	pc := shared.NewPageContext()
+	pc.Memos = map[string]string{"public-memo": "公開", "secret-memo": "秘匿", "scenario-secret-memo": "シナリオ秘匿"}
+
+	titles := func(memos []cthulhu6.SheetMemo) []string {
+		var out []string
+		for _, m := range memos {
+			out = append(out, m.Title)
+		}
+		return out
+	}
+	tests := []struct {
+		desc    string
+		preview bool
+		secret  bool
+		want    []string
+	}{
+		{"public only by default", false, false, []string{"公開メモ", "シナリオ公開メモ"}},
+		{"secret when asked", false, true, []string{"公開メモ", "秘匿メモ", "シナリオ公開メモ", "シナリオ秘匿メモ"}},
+		{"no secret for visitors", true, true, []string{"公開メモ", "シナリオ公開メモ"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			pc.Preview = tt.preview
+			if got := titles(sheetMemos(pc, tt.secret)); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// maxSource always rolls the highest face
+type maxSource struct{}
+
+func (maxSource) IntN(n int) int { return n - 1 }
+
+// constSource always returns the same value
+type constSource int
+
+func (c constSource) IntN(int) int { return int(c) }
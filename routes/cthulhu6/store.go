@@ -1,6 +1,19 @@
 package cthulhu6
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"charaxiv/dice"
+	"charaxiv/routes/gamesystem"
+	"charaxiv/storage"
 	"charaxiv/storage/coalesce"
 	"charaxiv/systems/cthulhu6"
 )
@@ -8,23 +21,31 @@ import (
 // Store wraps coalesce.Store with cthulhu6-specific typed access.
 type Store struct {
 	coalesce *coalesce.Store
+	roller   *dice.Roller
+	images   storage.Storage // character images; nil when none are configured
 }
 
 // NewStore creates a new cthulhu6 store backed by coalesce storage.
 func NewStore(c *coalesce.Store) *Store {
-	return &Store{coalesce: c}
+	return &Store{coalesce: c, roller: dice.Default()}
 }
 
-// load reads character data from coalesce (which flushes pending writes)
-func (s *Store) load(charID string) (*cthulhu6.Status, *cthulhu6.Skills, map[string]string) {
-	data, err := s.coalesce.Read(charID)
+// view reads raw character data from coalesce, merged with pending writes.
+// Pending writes are persisted according to the store's flush policy, not on every read.
+func (s *Store) view(charID string) map[string]any {
+	data, err := s.coalesce.View(context.Background(), charID)
 	if err != nil {
-		return cthulhu6.NewStatus(), cthulhu6.NewSkills(), make(map[string]string)
+		return map[string]any{}
 	}
+	return data
+}
+
+// load reads typed character data, starting from defaults
+func (s *Store) load(charID string) (*cthulhu6.Status, *cthulhu6.Skills, map[string]string) {
+	data := s.view(charID)
 
 	// Start with defaults
 	status := cthulhu6.NewStatus()
-	skills := cthulhu6.NewSkills()
 	memos := make(map[string]string)
 
 	// Merge status variables (only override fields that exist in loaded data)
@@ -60,9 +81,42 @@ func (s *Store) load(charID string) (*cthulhu6.Status, *cthulhu6.Skills, map[str
 		if db, ok := statusData["db"].(string); ok {
 			status.DB = db
 		}
+		// Parse generation settings
+		if genData, ok := statusData["generation"].(map[string]any); ok {
+			if v, ok := genData["mode"].(string); ok {
+				status.Generation.Mode = cthulhu6.GenerationMode(v)
+			}
+			if v, ok := genData["budget"].(float64); ok {
+				status.Generation.Budget = int(v)
+			}
+			if arr, ok := genData["array"].([]any); ok {
+				for _, n := range arr {
+					if v, ok := n.(float64); ok {
+						status.Generation.Array = append(status.Generation.Array, int(v))
+					}
+				}
+			}
+		}
+		// Parse era preset
+		if era, ok := statusData["era"].(string); ok {
+			status.Era = cthulhu6.Era(era)
+		}
+		// Parse house rules
+		if rulesData, ok := statusData["rules"]; ok {
+			decode(rulesData, &status.Rules)
+		}
+		// Parse sanity tracking
+		if sanityData, ok := statusData["sanity"]; ok {
+			decode(sanityData, &status.Sanity)
+		}
+		// Parse roll log
+		if rollsData, ok := statusData["rolls"]; ok {
+			decode(rollsData, &status.Rolls)
+		}
 	}
 
-	// Merge skills
+	// Merge skills over the defaults of the character's era
+	skills := cthulhu6.NewSkillsForEra(status.Era)
 	if skillsData, ok := data["skills"].(map[string]any); ok {
 		if catsData, ok := skillsData["categories"].(map[string]any); ok {
 			for catName, catData := range catsData {
@@ -90,6 +144,10 @@ func (s *Store) load(charID string) (*cthulhu6.Status, *cthulhu6.Skills, map[str
 				skills.Extra.Hobby = int(hobby)
 			}
 		}
+		// Parse occupation selection
+		if occData, ok := skillsData["occupation"]; ok {
+			decode(occData, &skills.Occupation)
+		}
 		// Parse custom skills
 		if customData, ok := skillsData["custom"].([]any); ok {
 			for _, cd := range customData {
@@ -215,39 +273,141 @@ func (s *Store) SetMemo(charID, memoID, value string) bool {
 	return true
 }
 
-// SetVariableBase sets a variable's base value
-func (s *Store) SetVariableBase(charID, key string, value int) *cthulhu6.Variable {
+// SetVariableBase sets a variable's base value under the character's generation rules.
+// It returns the changed bases, which include a swapped variable in array mode.
+func (s *Store) SetVariableBase(charID, key string, value int) (map[string]int, error) {
 	status, _, _ := s.load(charID)
-	if v, ok := status.Variables[key]; ok {
-		if value < v.Min {
-			value = v.Min
+	changes, err := status.SetBase(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return changes, s.coalesce.WriteBatch(charID, baseOps(changes))
+}
+
+// UpdateVariableBase updates a variable's base by delta under the character's generation rules
+func (s *Store) UpdateVariableBase(charID, key string, delta int) (map[string]int, error) {
+	status, _, _ := s.load(charID)
+	changes, err := status.AdjustBase(key, delta)
+	if err != nil {
+		return nil, err
+	}
+	return changes, s.coalesce.WriteBatch(charID, baseOps(changes))
+}
+
+// baseOps returns write ops for changed bases in display order
+func baseOps(bases map[string]int) []coalesce.Op {
+	ops := make([]coalesce.Op, 0, len(bases))
+	for _, key := range cthulhu6.VariableOrder {
+		if base, ok := bases[key]; ok {
+			ops = append(ops, coalesce.Op{Path: "status.variables." + key + ".base", Value: base})
 		}
-		if value > v.Max {
-			value = v.Max
+	}
+	return ops
+}
+
+// SetGeneration switches the character's generation mode, resetting bases the new mode requires
+func (s *Store) SetGeneration(charID string, g cthulhu6.Generation) error {
+	g, err := g.Normalize()
+	if err != nil {
+		return err
+	}
+	status, _, _ := s.load(charID)
+
+	ops := append([]coalesce.Op{{Path: "status.generation", Value: g}}, baseOps(status.ApplyGeneration(g))...)
+	return s.coalesce.WriteBatch(charID, ops)
+}
+
+// SetEra switches the character's era preset, migrating allocated skill points
+// to the new era's skill list
+func (s *Store) SetEra(charID string, era cthulhu6.Era) error {
+	if _, ok := cthulhu6.EraByID(era); !ok {
+		return fmt.Errorf("unknown era %q", era)
+	}
+	status, skills, _ := s.load(charID)
+	if status.Era == era {
+		return nil
+	}
+	migrated := status.MigrateSkills(skills, era)
+	return s.coalesce.WriteBatch(charID, []coalesce.Op{
+		{Path: "status.era", Value: era},
+		{Path: "skills.categories", Value: migrated.Categories},
+		{Path: "skills.custom", Value: migrated.Custom},
+	})
+}
+
+// SetRuleProfile selects the house rules. Switching to custom for the first
+// time starts from the formulas of the current profile.
+func (s *Store) SetRuleProfile(charID string, profile cthulhu6.RuleProfile) error {
+	if !slices.Contains(cthulhu6.RuleProfiles, profile) {
+		return fmt.Errorf("unknown rule profile %q", profile)
+	}
+	status, _, _ := s.load(charID)
+	rules := status.Rules
+	if profile == cthulhu6.RulesCustom && rules.Custom == nil {
+		rs := rules.Set().Clone()
+		rules.Custom = &rs
+	}
+	rules.Profile = profile
+	s.coalesce.Write(charID, "status.rules", rules)
+	return nil
+}
+
+// SetRuleFormula updates one formula of the custom rules. group is "computed",
+// "parameters", "skills" or "damageBonus" (bands in ParseDamageBands form).
+func (s *Store) SetRuleFormula(charID, group, key, formula string) error {
+	status, _, _ := s.load(charID)
+	rules := status.Rules
+	if rules.Profile != cthulhu6.RulesCustom || rules.Custom == nil {
+		return fmt.Errorf("rules are not custom")
+	}
+	rs := rules.Custom.Clone()
+	if group == "damageBonus" {
+		bands, err := cthulhu6.ParseDamageBands(formula)
+		if err != nil {
+			return err
 		}
-		v.Base = value
-		s.coalesce.Write(charID, "status.variables."+key+".base", value)
-		return &v
+		rs.DamageBonus = bands
+	} else if err := rs.SetFormula(group, key, formula); err != nil {
+		return err
 	}
+	rules.Custom = &rs
+	s.coalesce.Write(charID, "status.rules", rules)
 	return nil
 }
 
-// UpdateVariableBase updates a variable's base by delta
-func (s *Store) UpdateVariableBase(charID, key string, delta int) *cthulhu6.Variable {
+// RollVariables rolls the given variables (all of them when keys is empty)
+// and writes the new bases together with the updated roll log in one batch
+func (s *Store) RollVariables(charID string, keys ...string) ([]cthulhu6.StatRoll, error) {
 	status, _, _ := s.load(charID)
-	if v, ok := status.Variables[key]; ok {
-		newBase := v.Base + delta
-		if newBase < v.Min {
-			newBase = v.Min
+	if len(keys) == 0 {
+		keys = cthulhu6.VariableOrder
+	}
+
+	rolls := make([]cthulhu6.StatRoll, 0, len(keys))
+	ops := make([]coalesce.Op, 0, len(keys)+1)
+	for _, key := range keys {
+		v, ok := status.Variables[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", key)
+		}
+		formula, err := status.Generation.Formula(v)
+		if err != nil {
+			return nil, err
 		}
-		if newBase > v.Max {
-			newBase = v.Max
+		v.Dice = formula
+		roll, err := cthulhu6.RollVariable(s.roller, key, v)
+		if err != nil {
+			return nil, err
 		}
-		v.Base = newBase
-		s.coalesce.Write(charID, "status.variables."+key+".base", newBase)
-		return &v
+		rolls = append(rolls, roll)
+		ops = append(ops, coalesce.Op{Path: "status.variables." + key + ".base", Value: roll.Value})
 	}
-	return nil
+	ops = append(ops, coalesce.Op{Path: "status.rolls", Value: cthulhu6.AppendRolls(status.Rolls, rolls...)})
+
+	if err := s.coalesce.WriteBatch(charID, ops); err != nil {
+		return nil, err
+	}
+	return rolls, nil
 }
 
 // UpdateParameter updates a parameter by delta
@@ -294,6 +454,38 @@ func (s *Store) SetSkillExtra(charID string, job, hobby int) {
 	s.coalesce.Write(charID, "skills.extra", cthulhu6.SkillExtra{Job: job, Hobby: hobby})
 }
 
+// SetOccupation selects an occupation from the catalog, clearing its picks.
+// An empty id clears the occupation.
+func (s *Store) SetOccupation(charID, id string) error {
+	sel := cthulhu6.OccupationSelection{}
+	if id != "" {
+		o, ok := cthulhu6.OccupationByID(id)
+		if !ok {
+			return fmt.Errorf("unknown occupation %q", id)
+		}
+		sel = sel.Normalize(o)
+	}
+	s.coalesce.Write(charID, "skills.occupation", sel)
+	return nil
+}
+
+// SetOccupationChoices replaces the "choose N" picks of the selected occupation.
+// picks maps a choice slot index to the picked option indices.
+func (s *Store) SetOccupationChoices(charID string, picks map[int][]int) error {
+	_, skills, _ := s.load(charID)
+	o, ok := cthulhu6.OccupationByID(skills.Occupation.ID)
+	if !ok {
+		return fmt.Errorf("no occupation selected")
+	}
+	sel := cthulhu6.OccupationSelection{ID: o.ID, Choices: make([][]int, len(o.Choices))}
+	for i := range sel.Choices {
+		sel.Choices[i] = picks[i]
+	}
+	sel = sel.Normalize(o)
+	s.coalesce.Write(charID, "skills.occupation", sel)
+	return nil
+}
+
 // GetCustomSkill returns a custom skill by index
 func (s *Store) GetCustomSkill(charID string, index int) (cthulhu6.CustomSkill, bool) {
 	_, skills, _ := s.load(charID)
@@ -332,3 +524,412 @@ func (s *Store) UpdateCustomSkill(charID string, index int, cs cthulhu6.CustomSk
 	s.coalesce.Write(charID, "skills.custom", skills.Custom)
 	return true
 }
+
+// GetChecks returns the character's check history, oldest first
+func (s *Store) GetChecks(charID string) []cthulhu6.CheckRoll {
+	var checks []cthulhu6.CheckRoll
+	if checksData, ok := s.view(charID)["checks"]; ok {
+		decode(checksData, &checks)
+	}
+	return checks
+}
+
+// CheckSkill rolls a check against a single skill's total
+func (s *Store) CheckSkill(charID, key string, modifier int) (cthulhu6.CheckRoll, error) {
+	status, skills, _ := s.load(charID)
+	value, ok := status.SkillValue(skills, key)
+	if !ok {
+		return cthulhu6.CheckRoll{}, fmt.Errorf("unknown skill %q", key)
+	}
+	return s.recordCheck(charID, key, value, modifier)
+}
+
+// CheckGenre rolls a check against a multi-skill genre's total
+func (s *Store) CheckGenre(charID, key string, index, modifier int) (cthulhu6.CheckRoll, error) {
+	status, skills, _ := s.load(charID)
+	value, label, ok := status.GenreValue(skills, key, index)
+	if !ok {
+		return cthulhu6.CheckRoll{}, fmt.Errorf("unknown genre %s/%d", key, index)
+	}
+	return s.recordCheck(charID, label, value, modifier)
+}
+
+// CheckCustomSkill rolls a check against a custom skill's total
+func (s *Store) CheckCustomSkill(charID string, index, modifier int) (cthulhu6.CheckRoll, error) {
+	cs, ok := s.GetCustomSkill(charID, index)
+	if !ok {
+		return cthulhu6.CheckRoll{}, fmt.Errorf("unknown custom skill %d", index)
+	}
+	label := cs.Name
+	if label == "" {
+		label = "無名"
+	}
+	return s.recordCheck(charID, label, cs.Total(), modifier)
+}
+
+// CheckWeapon rolls an attack check against a weapon's linked skill
+func (s *Store) CheckWeapon(charID string, index, modifier int) (cthulhu6.CheckRoll, error) {
+	weapons := s.GetWeapons(charID)
+	if index < 0 || index >= len(weapons) {
+		return cthulhu6.CheckRoll{}, fmt.Errorf("weapon %d not found", index)
+	}
+	w := weapons[index]
+	status, skills, _ := s.load(charID)
+	value, ok := status.WeaponSkillValue(skills, w.Skill)
+	if !ok {
+		return cthulhu6.CheckRoll{}, fmt.Errorf("weapon %s: unknown skill %q", w.Name, w.Skill)
+	}
+	label := w.Skill
+	if w.Name != "" && w.Name != w.Skill {
+		label = fmt.Sprintf("%s(%s)", w.Skill, w.Name)
+	}
+	return s.recordCheck(charID, label, value, modifier)
+}
+
+// CheckVariable rolls a check against a characteristic times multiplier (e.g. STR×5)
+func (s *Store) CheckVariable(charID, key string, multiplier, modifier int) (cthulhu6.CheckRoll, error) {
+	status, _, _ := s.load(charID)
+	v, ok := status.Variables[key]
+	if !ok {
+		return cthulhu6.CheckRoll{}, fmt.Errorf("unknown variable %q", key)
+	}
+	label := fmt.Sprintf("%s×%d", key, multiplier)
+	return s.recordCheck(charID, label, v.Sum()*multiplier, modifier)
+}
+
+// recordCheck rolls a check and appends it to the character's history
+func (s *Store) recordCheck(charID, label string, value, modifier int) (cthulhu6.CheckRoll, error) {
+	check, err := cthulhu6.RollCheck(s.roller, label, value, modifier)
+	if err != nil {
+		return cthulhu6.CheckRoll{}, err
+	}
+	checks := cthulhu6.AppendChecks(s.GetChecks(charID), check)
+	if err := s.coalesce.Write(charID, "checks", checks); err != nil {
+		return cthulhu6.CheckRoll{}, err
+	}
+	return check, nil
+}
+
+// decode converts loosely typed stored data into out via JSON
+func decode(value any, out any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// GetWeapons returns the character's weapons
+func (s *Store) GetWeapons(charID string) []cthulhu6.Weapon {
+	data := s.view(charID)
+	var weapons []cthulhu6.Weapon
+	if raw, ok := data["weapons"]; ok {
+		decode(raw, &weapons)
+	}
+	return weapons
+}
+
+// AddWeapon appends a weapon from the catalog, or a blank one when catalogID is empty
+func (s *Store) AddWeapon(charID, catalogID string) error {
+	w := cthulhu6.Weapon{Damage: "1D3"}
+	if catalogID != "" {
+		var ok bool
+		if w, ok = cthulhu6.CatalogWeaponByID(catalogID); !ok {
+			return fmt.Errorf("unknown weapon %q", catalogID)
+		}
+	}
+	weapons := append(s.GetWeapons(charID), w)
+	s.coalesce.Write(charID, "weapons", weapons)
+	return nil
+}
+
+// SetWeaponField updates one field of a weapon
+func (s *Store) SetWeaponField(charID string, index int, field, value string) error {
+	weapons := s.GetWeapons(charID)
+	if index < 0 || index >= len(weapons) {
+		return fmt.Errorf("weapon %d not found", index)
+	}
+	if err := weapons[index].Set(field, value); err != nil {
+		return err
+	}
+	s.coalesce.Write(charID, "weapons", weapons)
+	return nil
+}
+
+// DeleteWeapon removes a weapon by index
+func (s *Store) DeleteWeapon(charID string, index int) bool {
+	weapons := s.GetWeapons(charID)
+	if index < 0 || index >= len(weapons) {
+		return false
+	}
+	weapons = append(weapons[:index], weapons[index+1:]...)
+	s.coalesce.Write(charID, "weapons", weapons)
+	return true
+}
+
+// GetInventory returns the character's items in display order
+func (s *Store) GetInventory(charID string) []cthulhu6.Item {
+	data := s.view(charID)
+	var items []cthulhu6.Item
+	if raw, ok := data["inventory"]; ok {
+		decode(raw, &items)
+	}
+	return items
+}
+
+// AddItem appends a blank item carrying one of it
+func (s *Store) AddItem(charID string) {
+	items := append(s.GetInventory(charID), cthulhu6.Item{Quantity: 1})
+	s.coalesce.Write(charID, "inventory", items)
+}
+
+// SetItemField updates one field of an item
+func (s *Store) SetItemField(charID string, index int, field, value string) error {
+	items := s.GetInventory(charID)
+	if index < 0 || index >= len(items) {
+		return fmt.Errorf("item %d not found", index)
+	}
+	if err := items[index].Set(field, value); err != nil {
+		return err
+	}
+	s.coalesce.Write(charID, "inventory", items)
+	return nil
+}
+
+// DeleteItem removes an item by index
+func (s *Store) DeleteItem(charID string, index int) bool {
+	items := s.GetInventory(charID)
+	if index < 0 || index >= len(items) {
+		return false
+	}
+	items = append(items[:index], items[index+1:]...)
+	s.coalesce.Write(charID, "inventory", items)
+	return true
+}
+
+// MoveItem moves an item by delta positions and reports whether the order changed
+func (s *Store) MoveItem(charID string, index, delta int) bool {
+	items := s.GetInventory(charID)
+	if !cthulhu6.MoveItem(items, index, delta) {
+		return false
+	}
+	s.coalesce.Write(charID, "inventory", items)
+	return true
+}
+
+// GetFinances returns the character's cash, assets and income override
+func (s *Store) GetFinances(charID string) cthulhu6.Finances {
+	data := s.view(charID)
+	var f cthulhu6.Finances
+	if raw, ok := data["finances"]; ok {
+		decode(raw, &f)
+	}
+	return f
+}
+
+// SetFinanceField updates cash, assets or the income override
+func (s *Store) SetFinanceField(charID, field, value string) error {
+	f := s.GetFinances(charID)
+	if err := f.Set(field, value); err != nil {
+		return err
+	}
+	s.coalesce.Write(charID, "finances", f)
+	return nil
+}
+
+// GetDamageRolls returns the character's damage rolls, oldest first
+func (s *Store) GetDamageRolls(charID string) []cthulhu6.DamageRoll {
+	data := s.view(charID)
+	var rolls []cthulhu6.DamageRoll
+	if raw, ok := data["damage"]; ok {
+		decode(raw, &rolls)
+	}
+	return rolls
+}
+
+// RollWeaponDamage rolls a weapon's damage and records it
+func (s *Store) RollWeaponDamage(charID string, index int) (cthulhu6.DamageRoll, error) {
+	weapons := s.GetWeapons(charID)
+	if index < 0 || index >= len(weapons) {
+		return cthulhu6.DamageRoll{}, fmt.Errorf("weapon %d not found", index)
+	}
+	status, _, _ := s.load(charID)
+	d, err := cthulhu6.RollDamage(s.roller, weapons[index], status)
+	if err != nil {
+		return cthulhu6.DamageRoll{}, err
+	}
+	s.coalesce.Write(charID, "damage", cthulhu6.AppendDamageRolls(s.GetDamageRolls(charID), d))
+	return d, nil
+}
+
+// GetGrowthReports returns the character's growth reports, oldest first
+func (s *Store) GetGrowthReports(charID string) []cthulhu6.GrowthReport {
+	data := s.view(charID)
+	var reports []cthulhu6.GrowthReport
+	if raw, ok := data["growth"]; ok {
+		decode(raw, &reports)
+	}
+	return reports
+}
+
+// ResolveGrowth rolls growth for every skill marked Grow and writes the
+// updated skills, cleared flags and the new report in one batch.
+// A report with no results is returned, but not recorded, when nothing is marked.
+func (s *Store) ResolveGrowth(charID string) (cthulhu6.GrowthReport, error) {
+	status, skills, _ := s.load(charID)
+	report, changed, err := cthulhu6.ResolveGrowth(s.roller, status, skills)
+	if err != nil || len(report.Results) == 0 {
+		return report, err
+	}
+
+	var ops []coalesce.Op
+	for _, cat := range cthulhu6.CategoryOrder {
+		for _, key := range changed.Skills[cat] {
+			ops = append(ops, coalesce.Op{
+				Path:  "skills.categories." + string(cat) + ".skills." + key,
+				Value: skills.Categories[cat].Skills[key],
+			})
+		}
+	}
+	if changed.Custom {
+		ops = append(ops, coalesce.Op{Path: "skills.custom", Value: skills.Custom})
+	}
+	reports := cthulhu6.AppendGrowthReport(s.GetGrowthReports(charID), report)
+	ops = append(ops, coalesce.Op{Path: "growth", Value: reports})
+
+	return report, s.coalesce.WriteBatch(charID, ops)
+}
+
+// SanCheck rolls a SAN check with a loss expression like "1/1D6", applies
+// the loss to SAN and records the check in one batch
+func (s *Store) SanCheck(charID, expr string) (cthulhu6.SanCheck, error) {
+	status, _, _ := s.load(charID)
+	san := status.EffectiveParameter("SAN")
+	check, err := cthulhu6.RollSanCheck(s.roller, expr, san, &status.Sanity)
+	if err != nil {
+		return cthulhu6.SanCheck{}, err
+	}
+	err = s.coalesce.WriteBatch(charID, []coalesce.Op{
+		{Path: "status.parameters.SAN", Value: check.SanAfter()},
+		{Path: "status.sanity", Value: status.Sanity},
+	})
+	return check, err
+}
+
+// ResetSanSession clears the SAN lost in the current session
+func (s *Store) ResetSanSession(charID string) error {
+	status, _, _ := s.load(charID)
+	status.Sanity.ResetSession()
+	return s.coalesce.Write(charID, "status.sanity", status.Sanity)
+}
+
+// ToggleSanityFlag toggles the temporary or indefinite insanity flag
+func (s *Store) ToggleSanityFlag(charID string, kind cthulhu6.InsanityKind) error {
+	status, _, _ := s.load(charID)
+	switch kind {
+	case cthulhu6.InsanityTemporary:
+		status.Sanity.Temporary = !status.Sanity.Temporary
+	case cthulhu6.InsanityIndefinite:
+		status.Sanity.Indefinite = !status.Sanity.Indefinite
+	default:
+		return fmt.Errorf("no flag for insanity kind %q", kind)
+	}
+	return s.coalesce.Write(charID, "status.sanity", status.Sanity)
+}
+
+// AddInsanity records an insanity, phobia or mania
+func (s *Store) AddInsanity(charID, label string, kind cthulhu6.InsanityKind) error {
+	if !kind.Valid() {
+		return fmt.Errorf("unknown insanity kind %q", kind)
+	}
+	status, _, _ := s.load(charID)
+	status.Sanity.Insanities = append(status.Sanity.Insanities, cthulhu6.Insanity{
+		Label: label,
+		Kind:  kind,
+		Time:  time.Now(),
+	})
+	return s.coalesce.Write(charID, "status.sanity", status.Sanity)
+}
+
+// DeleteInsanity removes a recorded insanity by index
+func (s *Store) DeleteInsanity(charID string, index int) bool {
+	status, _, _ := s.load(charID)
+	list := status.Sanity.Insanities
+	if index < 0 || index >= len(list) {
+		return false
+	}
+	status.Sanity.Insanities = append(list[:index], list[index+1:]...)
+	s.coalesce.Write(charID, "status.sanity", status.Sanity)
+	return true
+}
+
+// ImportExternal replaces a character with one read from another character
+// sheet service
+func (s *Store) ImportExternal(charID string, x *cthulhu6.ExternalSheet) error {
+	var data map[string]any
+	if err := decode(map[string]any{"status": x.Status, "skills": x.Skills, "memos": x.Memos}, &data); err != nil {
+		return err
+	}
+	ops, err := gamesystem.ReplaceOps(documentFields, data)
+	if err != nil {
+		return err
+	}
+	return s.coalesce.WriteBatch(charID, ops)
+}
+
+// PortraitKey returns the storage key of a character's pinned portrait
+func PortraitKey(charID string) string {
+	return "characters/" + charID + "/portrait"
+}
+
+// maxPortraitSize limits uploaded portraits
+const maxPortraitSize = 5 << 20
+
+// SetPortrait pins an uploaded image as the character's portrait, replacing
+// any earlier one. Only images Udonarium can load are accepted.
+func (s *Store) SetPortrait(ctx context.Context, charID string, image []byte) error {
+	if s.images == nil {
+		return errors.New("image storage is not configured")
+	}
+	if len(image) > maxPortraitSize {
+		return fmt.Errorf("portrait is larger than %d MiB", maxPortraitSize>>20)
+	}
+	if _, ok := cthulhu6.ImageExtension(image); !ok {
+		return errors.New("portrait is not a PNG, JPEG, GIF or WebP image")
+	}
+	return s.images.Upload(ctx, PortraitKey(charID), bytes.NewReader(image), http.DetectContentType(image))
+}
+
+// HasPortrait reports whether the character has a pinned portrait
+func (s *Store) HasPortrait(ctx context.Context, charID string) bool {
+	if s.images == nil {
+		return false
+	}
+	ok, err := s.images.Exists(ctx, PortraitKey(charID))
+	return err == nil && ok
+}
+
+// DeletePortrait removes the pinned portrait, if any
+func (s *Store) DeletePortrait(ctx context.Context, charID string) error {
+	if !s.HasPortrait(ctx, charID) {
+		return nil
+	}
+	return s.images.Delete(ctx, PortraitKey(charID))
+}
+
+// GetPortrait returns the pinned portrait, or nil when there is none
+func (s *Store) GetPortrait(ctx context.Context, charID string) ([]byte, error) {
+	if s.images == nil {
+		return nil, nil
+	}
+	key := PortraitKey(charID)
+	if ok, err := s.images.Exists(ctx, key); err != nil || !ok {
+		return nil, err
+	}
+	r, err := s.images.Download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,75 @@
+package cthulhu6
+
+import (
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/routes/gamesystem"
+	"charaxiv/storage"
+	"charaxiv/storage/coalesce"
+	"charaxiv/systems/cthulhu6"
+	"charaxiv/templates/shared"
+)
+
+// DefaultDocument is the character served at the system root
+const DefaultDocument = "demo"
+
+// documentFields are the top-level fields of a stored character
+var documentFields = []gamesystem.Field{
+	gamesystem.FieldOf[cthulhu6.Status]("status"),
+	gamesystem.FieldOf[cthulhu6.Skills]("skills"),
+	gamesystem.FieldOf[map[string]string]("memos"),
+	gamesystem.FieldOf[[]cthulhu6.CheckRoll]("checks"),
+	gamesystem.FieldOf[[]cthulhu6.GrowthReport]("growth"),
+	gamesystem.FieldOf[[]cthulhu6.Weapon]("weapons"),
+	gamesystem.FieldOf[[]cthulhu6.DamageRoll]("damage"),
+	gamesystem.FieldOf[[]cthulhu6.Item]("inventory"),
+	gamesystem.FieldOf[cthulhu6.Finances]("finances"),
+}
+
+// System registers CoC 6th edition with the application router
+type System struct {
+	*gamesystem.Documents
+	store *Store
+}
+
+var _ gamesystem.GameSystem = (*System)(nil)
+
+// NewSystem creates the cthulhu6 game system backed by coalesce storage,
+// reading character images such as the pinned portrait from images
+func NewSystem(cs *coalesce.Store, images storage.Storage) *System {
+	store := NewStore(cs)
+	store.images = images
+	return &System{Documents: gamesystem.NewDocuments(cs, DefaultDocument, documentFields...), store: store}
+}
+
+// ID returns the URL prefix
+func (s *System) ID() string { return "cthulhu6" }
+
+// Name returns the display name
+func (s *System) Name() string { return "クトゥルフ神話TRPG 第6版" }
+
+// Router builds the system's routes
+func (s *System) Router() chi.Router { return Routes(s.store) }
+
+// Summary describes a character by occupation, parameters and damage bonus
+func (s *System) Summary(charID string) shared.CharacterSummary {
+	status := s.store.GetStatus(charID)
+	skills := s.store.GetSkills(charID)
+	out := shared.CharacterSummary{
+		System:     s.ID(),
+		SystemName: s.Name(),
+		ID:         charID,
+		URL:        "/" + s.ID() + "/",
+		Memo:       s.store.GetMemo(charID, "public-memo"),
+	}
+	if o, ok := cthulhu6.OccupationByID(skills.Occupation.ID); ok {
+		out.Title = o.Name
+	}
+	for _, key := range cthulhu6.ParameterOrder {
+		out.Stats = append(out.Stats, shared.SummaryStat{Label: key, Value: strconv.Itoa(status.EffectiveParameter(key))})
+	}
+	out.Stats = append(out.Stats, shared.SummaryStat{Label: "DB", Value: status.DamageBonus()})
+	return out
+}
@@ -0,0 +1,251 @@
+package cthulhu7
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/systems/cthulhu7"
+	"charaxiv/templates/components"
+	"charaxiv/templates/pages"
+	"charaxiv/templates/shared"
+)
+
+// html wraps a templ.Component handler, setting the Content-Type header.
+func html(c func(r *http.Request) templ.Component) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		c(r).Render(r.Context(), w)
+	}
+}
+
+// buildPageContext creates a PageContext with memos loaded from the store
+func buildPageContext(store *Store, charID, basePath string) shared.PageContext {
+	ctx := shared.NewPageContext()
+	ctx.BasePath = basePath
+	memoIDs := []string{"public-memo", "secret-memo", "scenario-public-memo", "scenario-secret-memo"}
+	for _, id := range memoIDs {
+		ctx.Memos[id] = store.GetMemo(charID, id)
+	}
+	return ctx
+}
+
+// buildSheetState loads the character and converts it to template types
+func buildSheetState(store *Store, charID, basePath string) shared.Cthulhu7SheetState {
+	pc := buildPageContext(store, charID, basePath)
+	return cthulhu7.BuildSheetState(pc, store.GetStatus(charID), store.GetSkills(charID))
+}
+
+// sheetFragments renders the status and skills panels for OOB swaps. Almost
+// every value on the 7th edition sheet feeds a percentile or point total
+// shown in the other panel, so mutations refresh both.
+func sheetFragments(store *Store, charID, basePath string) templ.Component {
+	return components.Cthulhu7SheetFragments(buildSheetState(store, charID, basePath))
+}
+
+// intParam parses an integer URL parameter, defaulting to -1
+func intParam(r *http.Request, name string) int {
+	n := -1
+	fmt.Sscanf(chi.URLParam(r, name), "%d", &n)
+	return n
+}
+
+// delta parses the delta query parameter of an adjust request
+func delta(r *http.Request) int {
+	d := 0
+	fmt.Sscanf(r.URL.Query().Get("delta"), "%d", &d)
+	return d
+}
+
+// Routes returns a chi.Router with all cthulhu7-specific routes.
+func Routes(store *Store) chi.Router {
+	r := chi.NewRouter()
+
+	// For now, use a fixed character ID until we have proper routing
+	const charID = DefaultDocument
+	const basePath = "/cthulhu7"
+
+	// Character sheet
+	r.Get("/", html(func(r *http.Request) templ.Component {
+		return pages.Cthulhu7Sheet(buildSheetState(store, charID, basePath))
+	}))
+
+	// Preview mode toggle - returns targeted fragments with OOB swaps
+	r.Post("/api/preview/on", html(func(r *http.Request) templ.Component {
+		ctx := buildPageContext(store, charID, basePath)
+		ctx.Preview = true
+		return pages.Cthulhu7PreviewModeFragments(ctx)
+	}))
+
+	r.Post("/api/preview/off", html(func(r *http.Request) templ.Component {
+		ctx := buildPageContext(store, charID, basePath)
+		ctx.Preview = false
+		return pages.Cthulhu7PreviewModeFragments(ctx)
+	}))
+
+	// Memo update
+	r.Post("/api/memo/{id}/set", func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		r.ParseForm()
+		store.SetMemo(charID, id, r.FormValue(id))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Status value set (direct value from input): characteristics, 幸運, age and parameters
+	r.Post("/api/status/{key}/set", func(w http.ResponseWriter, r *http.Request) {
+		key := chi.URLParam(r, "key")
+		r.ParseForm()
+		var value int
+		if _, err := fmt.Sscanf(r.FormValue(strings.ReplaceAll(key, "-", "_")), "%d", &value); err != nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// Rejected values re-render the panels so the input reverts
+		switch {
+		case key == "status-LUK":
+			store.SetLuck(charID, value)
+		case key == "status-AGE":
+			store.SetAge(charID, value)
+		case strings.HasPrefix(key, "param-"):
+			store.SetParameter(charID, strings.TrimPrefix(key, "param-"), value)
+		case strings.HasPrefix(key, "status-"):
+			if _, ok := store.GetStatus(charID).Variables[strings.TrimPrefix(key, "status-")]; !ok {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			store.SetVariableBase(charID, strings.TrimPrefix(key, "status-"), value)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		sheetFragments(store, charID, basePath).Render(r.Context(), w)
+	})
+
+	// Status value adjustment: characteristics, 幸運, age, parameters and extra points
+	r.Post("/api/status/{key}/adjust", html(func(r *http.Request) templ.Component {
+		key := chi.URLParam(r, "key")
+		d := delta(r)
+
+		// Out-of-range adjustments still re-render so the input shows the stored value
+		switch {
+		case key == "extra-job" || key == "extra-hobby":
+			extra := store.GetSkills(charID).Extra
+			if key == "extra-job" {
+				extra.Job += d
+			} else {
+				extra.Hobby += d
+			}
+			store.SetSkillExtra(charID, extra)
+		case key == "status-LUK":
+			store.SetLuck(charID, min(max(store.GetStatus(charID).Luck+d, 0), 99))
+		case key == "status-AGE":
+			store.SetAge(charID, min(max(store.GetStatus(charID).Age+d, 15), 99))
+		case strings.HasPrefix(key, "param-"):
+			if err := store.AdjustParameter(charID, strings.TrimPrefix(key, "param-"), d); err != nil {
+				return shared.Empty()
+			}
+		default:
+			if err := store.AdjustVariableBase(charID, strings.TrimPrefix(key, "status-"), d); err != nil {
+				return shared.Empty()
+			}
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Roll all characteristics and 幸運 from their dice formulas
+	r.Post("/api/status/random", html(func(r *http.Request) templ.Component {
+		if err := store.RollVariables(charID); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Select the 職業P formula
+	r.Post("/api/status/job-formula", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		if err := store.SetJobFormula(charID, r.FormValue("formula")); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Skill field adjustment (job, hobby, growth)
+	r.Post("/api/skill/{key}/{field}/adjust", html(func(r *http.Request) templ.Component {
+		if err := store.AdjustSkill(charID, chi.URLParam(r, "key"), chi.URLParam(r, "field"), delta(r)); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Add genre to multi-skill
+	r.Post("/api/skill/{key}/genre/add", html(func(r *http.Request) templ.Component {
+		if err := store.AddGenre(charID, chi.URLParam(r, "key")); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Delete genre from multi-skill
+	r.Post("/api/skill/{key}/genre/{index}/delete", html(func(r *http.Request) templ.Component {
+		if err := store.DeleteGenre(charID, chi.URLParam(r, "key"), intParam(r, "index")); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Update genre label; known genres such as 近接戦闘(格闘) have their own initial value
+	r.Post("/api/skill/{key}/genre/{index}/label", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		if err := store.SetGenreLabel(charID, chi.URLParam(r, "key"), intParam(r, "index"), r.FormValue("label")); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Adjust genre field (job, hobby, growth)
+	r.Post("/api/skill/{key}/genre/{index}/{field}/adjust", html(func(r *http.Request) templ.Component {
+		if err := store.AdjustGenre(charID, chi.URLParam(r, "key"), intParam(r, "index"), chi.URLParam(r, "field"), delta(r)); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Custom skill: add
+	r.Post("/api/skill/custom/add", html(func(r *http.Request) templ.Component {
+		if err := store.AddCustomSkill(charID); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Custom skill: delete
+	r.Post("/api/skill/custom/{index}/delete", html(func(r *http.Request) templ.Component {
+		if err := store.DeleteCustomSkill(charID, intParam(r, "index")); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Custom skill: name update
+	r.Post("/api/skill/custom/{index}/name", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		store.SetCustomSkillName(charID, intParam(r, "index"), r.FormValue("name"))
+		return shared.Empty()
+	}))
+
+	// Custom skill: field adjustment (job, hobby, growth)
+	r.Post("/api/skill/custom/{index}/{field}/adjust", html(func(r *http.Request) templ.Component {
+		if err := store.AdjustCustomSkill(charID, intParam(r, "index"), chi.URLParam(r, "field"), delta(r)); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	return r
+}
@@ -0,0 +1,491 @@
+package cthulhu7
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/dice"
+	"charaxiv/routes/gamesystem"
+	"charaxiv/storage/coalesce"
+	"charaxiv/systems/cthulhu7"
+)
+
+// RouteTest defines a test case for a route
+type RouteTest struct {
+	Method       string
+	Route        string
+	Desc         string
+	TestURL      string       // Actual URL to test (with params filled in)
+	Form         url.Values   // Form data to send
+	Query        string       // Query string (e.g., "delta=1")
+	Setup        func(*Store) // Optional setup before test
+	WantCode     int
+	WantContains []string // Strings that should be in response body
+}
+
+// routeTests defines all routes with their test cases
+var routeTests = []RouteTest{
+	{
+		Method:       "GET",
+		Route:        "/cthulhu7/",
+		Desc:         "Sheet page",
+		TestURL:      "/cthulhu7/",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"CharaXiv", "/cthulhu7/api/", "text/html", "ビルド", `value="格闘"`},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/preview/on",
+		Desc:         "Enable preview mode",
+		TestURL:      "/cthulhu7/api/preview/on",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"memo-group"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/preview/off",
+		Desc:         "Disable preview mode",
+		TestURL:      "/cthulhu7/api/preview/off",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"memo-group"},
+	},
+	{
+		Method:   "POST",
+		Route:    "/cthulhu7/api/memo/{id}/set",
+		Desc:     "Set memo",
+		TestURL:  "/cthulhu7/api/memo/public-memo/set",
+		Form:     url.Values{"public-memo": {"test memo"}},
+		WantCode: http.StatusNoContent,
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/{key}/set",
+		Desc:         "Set characteristic directly",
+		TestURL:      "/cthulhu7/api/status/status-STR/set",
+		Form:         url.Values{"status_STR": {"70"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel", `value="70"`},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/{key}/set",
+		Desc:         "Set luck directly",
+		TestURL:      "/cthulhu7/api/status/status-LUK/set",
+		Form:         url.Values{"status_LUK": {"80"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", `value="80"`},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/{key}/set",
+		Desc:         "Set parameter directly",
+		TestURL:      "/cthulhu7/api/status/param-SAN/set",
+		Form:         url.Values{"param_SAN": {"42"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", `value="42"`},
+	},
+	{
+		Method:   "POST",
+		Route:    "/cthulhu7/api/status/{key}/set",
+		Desc:     "Set unknown status key",
+		TestURL:  "/cthulhu7/api/status/status-XXX/set",
+		Form:     url.Values{"status_XXX": {"50"}},
+		WantCode: http.StatusNoContent,
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/{key}/adjust",
+		Desc:         "Adjust characteristic",
+		TestURL:      "/cthulhu7/api/status/status-DEX/adjust",
+		Query:        "delta=5",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/{key}/adjust",
+		Desc:         "Adjust age",
+		TestURL:      "/cthulhu7/api/status/status-AGE/adjust",
+		Query:        "delta=1",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", `value="26"`},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/{key}/adjust",
+		Desc:         "Adjust parameter (HP)",
+		TestURL:      "/cthulhu7/api/status/param-HP/adjust",
+		Query:        "delta=-1",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/{key}/adjust",
+		Desc:         "Adjust extra job points",
+		TestURL:      "/cthulhu7/api/status/extra-job/adjust",
+		Query:        "delta=10",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "extra-job"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/random",
+		Desc:         "Roll all characteristics",
+		TestURL:      "/cthulhu7/api/status/random",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/status/job-formula",
+		Desc:         "Select job point formula",
+		TestURL:      "/cthulhu7/api/status/job-formula",
+		Form:         url.Values{"formula": {"EDU*2+DEX*2"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/skill/{key}/{field}/adjust",
+		Desc:         "Adjust skill job points",
+		TestURL:      "/cthulhu7/api/skill/目星/job/adjust",
+		Query:        "delta=5",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "skill-目星-job"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/skill/{key}/genre/add",
+		Desc:         "Add genre to multi skill",
+		TestURL:      "/cthulhu7/api/skill/射撃/genre/add",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "genre-射撃-0"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/skill/{key}/genre/{index}/delete",
+		Desc:         "Delete genre from multi skill",
+		TestURL:      "/cthulhu7/api/skill/近接戦闘/genre/0/delete",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/skill/{key}/genre/{index}/label",
+		Desc:         "Update genre label",
+		TestURL:      "/cthulhu7/api/skill/近接戦闘/genre/0/label",
+		Form:         url.Values{"label": {"刀剣"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "刀剣"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/skill/{key}/genre/{index}/{field}/adjust",
+		Desc:         "Adjust genre hobby points",
+		TestURL:      "/cthulhu7/api/skill/近接戦闘/genre/0/hobby/adjust",
+		Query:        "delta=10",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "genre-近接戦闘-0-hobby"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/skill/custom/add",
+		Desc:         "Add custom skill",
+		TestURL:      "/cthulhu7/api/skill/custom/add",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "custom-0"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/skill/custom/{index}/delete",
+		Desc:         "Delete custom skill",
+		TestURL:      "/cthulhu7/api/skill/custom/0/delete",
+		Setup:        func(s *Store) { s.AddCustomSkill(DefaultDocument) },
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel"},
+	},
+	{
+		Method:   "POST",
+		Route:    "/cthulhu7/api/skill/custom/{index}/name",
+		Desc:     "Rename custom skill",
+		TestURL:  "/cthulhu7/api/skill/custom/0/name",
+		Form:     url.Values{"name": {"読唇術"}},
+		Setup:    func(s *Store) { s.AddCustomSkill(DefaultDocument) },
+		WantCode: http.StatusOK,
+	},
+	{
+		Method:       "POST",
+		Route:        "/cthulhu7/api/skill/custom/{index}/{field}/adjust",
+		Desc:         "Adjust custom skill growth",
+		TestURL:      "/cthulhu7/api/skill/custom/0/growth/adjust",
+		Query:        "delta=3",
+		Setup:        func(s *Store) { s.AddCustomSkill(DefaultDocument) },
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "custom-0-growth"},
+	},
+}
+
+// TestRoutes runs all route tests from the table
+func TestRoutes(t *testing.T) {
+	for _, tt := range routeTests {
+		t.Run(tt.Desc, func(t *testing.T) {
+			r, store, cleanup := setupTestRouter(t)
+			defer cleanup()
+
+			if tt.Setup != nil {
+				tt.Setup(store)
+			}
+
+			testURL := tt.TestURL
+			if tt.Query != "" {
+				testURL += "?" + tt.Query
+			}
+
+			var req *http.Request
+			if tt.Form != nil {
+				req = httptest.NewRequest(tt.Method, testURL, strings.NewReader(tt.Form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			} else {
+				req = httptest.NewRequest(tt.Method, testURL, nil)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.WantCode {
+				t.Errorf("Expected status %d, got %d", tt.WantCode, w.Code)
+			}
+
+			fullResponse := w.Body.String() + w.Header().Get("Content-Type")
+			for _, want := range tt.WantContains {
+				if !strings.Contains(fullResponse, want) {
+					t.Errorf("Expected response to contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+// TestAllRoutesHaveTests verifies every registered route has a test case
+func TestAllRoutesHaveTests(t *testing.T) {
+	r, _, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	testedRoutes := make(map[string]bool)
+	for _, tt := range routeTests {
+		testedRoutes[tt.Method+" "+tt.Route] = true
+	}
+
+	registeredSet := make(map[string]bool)
+	chi.Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		key := method + " " + route
+		registeredSet[key] = true
+		if !testedRoutes[key] {
+			t.Errorf("Route has no test: %s", key)
+		}
+		return nil
+	})
+
+	for _, tt := range routeTests {
+		key := tt.Method + " " + tt.Route
+		if !registeredSet[key] {
+			t.Errorf("Test exists for unregistered route: %s (%s)", key, tt.Desc)
+		}
+	}
+}
+
+// setupTestRouter creates a test router with a fresh store.
+func setupTestRouter(t *testing.T) (chi.Router, *Store, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "cthulhu7-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+
+	backend, err := coalesce.NewDiskBackend(filepath.Join(tmpDir, "characters"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create disk backend: %v", err)
+	}
+
+	cs, err := coalesce.New(coalesce.Config{
+		DBPath:  filepath.Join(tmpDir, "buffer.db"),
+		Backend: backend,
+	})
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create coalesce store: %v", err)
+	}
+
+	store := NewStore(cs)
+	r := chi.NewRouter()
+	r.Mount("/cthulhu7", Routes(store))
+
+	cleanup := func() {
+		cs.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return r, store, cleanup
+}
+
+// post sends a POST request with optional form values
+func post(r chi.Router, target string, form url.Values) *httptest.ResponseRecorder {
+	var req *http.Request
+	if form != nil {
+		req = httptest.NewRequest("POST", target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = httptest.NewRequest("POST", target, nil)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestJobFormulaChangesPoints tests that the selected formula decides 職業P
+func TestJobFormulaChangesPoints(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	store.SetVariableBase(DefaultDocument, "EDU", 70)
+	store.SetVariableBase(DefaultDocument, "DEX", 80)
+	if got := store.GetStatus(DefaultDocument).JobPoints(); got != 280 {
+		t.Fatalf("Expected EDU×4 = 280, got %d", got)
+	}
+
+	post(r, "/cthulhu7/api/status/job-formula", url.Values{"formula": {"EDU*2+DEX*2"}})
+	if got := store.GetStatus(DefaultDocument).JobPoints(); got != 300 {
+		t.Errorf("Expected EDU×2+DEX×2 = 300, got %d", got)
+	}
+
+	w := post(r, "/cthulhu7/api/status/job-formula", url.Values{"formula": {"EDU*9"}})
+	if w.Body.Len() != 0 {
+		t.Error("Expected an unknown formula to be rejected")
+	}
+	if got := store.GetStatus(DefaultDocument).JobFormula; got != "EDU*2+DEX*2" {
+		t.Errorf("Expected formula to stay EDU*2+DEX*2, got %s", got)
+	}
+}
+
+// TestStatusAdjustClampsToRange tests that adjustments stop at the characteristic range
+func TestStatusAdjustClampsToRange(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	post(r, "/cthulhu7/api/status/status-SIZ/adjust?delta=-100", nil)
+	if got := store.GetStatus(DefaultDocument).Variables["SIZ"].Base; got != 40 {
+		t.Errorf("Expected SIZ to stop at 40, got %d", got)
+	}
+
+	w := post(r, "/cthulhu7/api/status/status-STR/set", url.Values{"status_STR": {"120"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected rejected value to re-render, got %d", w.Code)
+	}
+	if got := store.GetStatus(DefaultDocument).Variables["STR"].Base; got != 55 {
+		t.Errorf("Expected STR to stay 55, got %d", got)
+	}
+}
+
+// TestStatusRandomRollsAllVariables tests that rolling writes every base and 幸運 within range
+func TestStatusRandomRollsAllVariables(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	store.roller = dice.NewRoller(maxSource{})
+
+	if w := post(r, "/cthulhu7/api/status/random", nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	status := store.GetStatus(DefaultDocument)
+	for _, key := range cthulhu7.VariableOrder {
+		if v := status.Variables[key]; v.Base != v.Max {
+			t.Errorf("%s: expected max roll clamped to %d, got %d", key, v.Max, v.Base)
+		}
+	}
+	if status.Luck != 90 {
+		t.Errorf("Expected 幸運 90, got %d", status.Luck)
+	}
+}
+
+// TestGenreLabelChangesInitialValue tests that a known genre label uses its own initial value
+func TestGenreLabelChangesInitialValue(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	post(r, "/cthulhu7/api/skill/射撃/genre/add", nil)
+	w := post(r, "/cthulhu7/api/skill/射撃/genre/0/label", url.Values{"label": {"拳銃"}})
+
+	status := store.GetStatus(DefaultDocument)
+	if got := status.GenreInitialValue("射撃", "拳銃"); got != 20 {
+		t.Fatalf("Expected 射撃(拳銃) to start at 20, got %d", got)
+	}
+	if !strings.Contains(w.Body.String(), `value="拳銃"`) {
+		t.Error("Expected the relabelled genre in the skills panel")
+	}
+}
+
+// TestMythosLowersMaxSanity tests that クトゥルフ神話 points lower the SAN ceiling
+func TestMythosLowersMaxSanity(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	post(r, "/cthulhu7/api/skill/クトゥルフ神話/growth/adjust?delta=6", nil)
+	if got := store.GetStatus(DefaultDocument).MaxSanity(store.GetSkills(DefaultDocument)); got != 93 {
+		t.Errorf("Expected max SAN 93, got %d", got)
+	}
+}
+
+// maxSource always rolls the highest face
+type maxSource struct{}
+
+func (maxSource) IntN(n int) int { return n - 1 }
+
+// TestSystemExportImport tests that an exported character imports back and stray fields are rejected
+func TestSystemExportImport(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	sys := &System{Documents: gamesystem.NewDocuments(store.coalesce, DefaultDocument, documentFields...), store: store}
+
+	store.SetLuck(DefaultDocument, 70)
+	store.AdjustSkill(DefaultDocument, "目星", "job", 30)
+	data, err := sys.Export(DefaultDocument)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if err := sys.Import("copy", data); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if got := store.GetStatus("copy").Luck; got != 70 {
+		t.Errorf("Expected imported 幸運 70, got %d", got)
+	}
+	if got, _ := store.GetStatus("copy").SkillValue(store.GetSkills("copy"), "目星"); got != 55 {
+		t.Errorf("Expected imported 目星 55, got %d", got)
+	}
+	if got := sys.Summary("copy").Stats; got[3].Value != "70" {
+		t.Errorf("Expected 幸運 in the summary, got %v", got)
+	}
+
+	if err := sys.Import("copy", map[string]any{"weapons": []any{}}); err == nil {
+		t.Error("Expected an unknown field to be rejected")
+	}
+	if err := sys.Import("copy", map[string]any{"status": "x"}); err == nil {
+		t.Error("Expected a malformed status to be rejected")
+	}
+	if err := sys.Import("copy", map[string]any{"skills": map[string]any{"custom": 1}}); err == nil {
+		t.Error("Expected malformed skills to be rejected")
+	}
+	if got := store.GetStatus("copy").Luck; got != 70 {
+		t.Errorf("Expected a rejected import to leave the character unchanged, got 幸運 %d", got)
+	}
+}
@@ -0,0 +1,372 @@
+package cthulhu7
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"charaxiv/dice"
+	"charaxiv/storage/coalesce"
+	"charaxiv/systems/cthulhu7"
+)
+
+// Store wraps coalesce.Store with cthulhu7-specific typed access.
+type Store struct {
+	coalesce *coalesce.Store
+	roller   *dice.Roller
+}
+
+// NewStore creates a new cthulhu7 store backed by coalesce storage.
+func NewStore(c *coalesce.Store) *Store {
+	return &Store{coalesce: c, roller: dice.Default()}
+}
+
+// view reads raw character data from coalesce, merged with pending writes.
+func (s *Store) view(charID string) map[string]any {
+	data, err := s.coalesce.View(context.Background(), charID)
+	if err != nil {
+		return map[string]any{}
+	}
+	return data
+}
+
+// decode converts a raw JSON value into a typed value via a JSON round trip
+func decode(value any, out any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// storedStatus is the stored status; nil fields keep their defaults
+type storedStatus struct {
+	Variables map[string]struct {
+		Base *int `json:"base"`
+		Perm *int `json:"perm"`
+		Temp *int `json:"temp"`
+	} `json:"variables"`
+	Parameters map[string]*int `json:"parameters"`
+	Luck       *int            `json:"luck"`
+	Age        *int            `json:"age"`
+	JobFormula string          `json:"jobFormula"`
+}
+
+// storedSkills is the stored skills; only built-in skills known to the system are kept
+type storedSkills struct {
+	Categories map[cthulhu7.SkillCategory]struct {
+		Skills map[string]cthulhu7.Skill `json:"skills"`
+	} `json:"categories"`
+	Custom []cthulhu7.CustomSkill `json:"custom"`
+	Extra  cthulhu7.SkillExtra    `json:"extra"`
+}
+
+// load reads typed character data, starting from defaults
+func (s *Store) load(charID string) (*cthulhu7.Status, *cthulhu7.Skills, map[string]string) {
+	data := s.view(charID)
+
+	status := cthulhu7.NewStatus()
+	skills := cthulhu7.NewSkills()
+	memos := make(map[string]string)
+
+	var st storedStatus
+	if raw, ok := data["status"]; ok && decode(raw, &st) == nil {
+		for key, sv := range st.Variables {
+			v, ok := status.Variables[key]
+			if !ok {
+				continue
+			}
+			if sv.Base != nil {
+				v.Base = *sv.Base
+			}
+			if sv.Perm != nil {
+				v.Perm = *sv.Perm
+			}
+			if sv.Temp != nil {
+				v.Temp = *sv.Temp
+			}
+			status.Variables[key] = v
+		}
+		for key, val := range st.Parameters {
+			if _, ok := status.Parameters[key]; ok {
+				status.Parameters[key] = val
+			}
+		}
+		if st.Luck != nil {
+			status.Luck = *st.Luck
+		}
+		if st.Age != nil {
+			status.Age = *st.Age
+		}
+		if slices.Contains(cthulhu7.JobFormulas, st.JobFormula) {
+			status.JobFormula = st.JobFormula
+		}
+	}
+
+	var sk storedSkills
+	if raw, ok := data["skills"]; ok && decode(raw, &sk) == nil {
+		for _, cat := range sk.Categories {
+			for key, skill := range cat.Skills {
+				if d, ok := cthulhu7.SkillDefByKey(key); ok && d.Multi == skill.IsMulti() {
+					skills.SetSkill(key, skill)
+				}
+			}
+		}
+		if sk.Custom != nil {
+			skills.Custom = sk.Custom
+		}
+		skills.Extra = sk.Extra
+	}
+
+	if memosData, ok := data["memos"].(map[string]any); ok {
+		for k, v := range memosData {
+			if str, ok := v.(string); ok {
+				memos[k] = str
+			}
+		}
+	}
+
+	return status, skills, memos
+}
+
+// GetStatus returns the status for a character
+func (s *Store) GetStatus(charID string) *cthulhu7.Status {
+	status, _, _ := s.load(charID)
+	return status
+}
+
+// GetSkills returns the skills for a character
+func (s *Store) GetSkills(charID string) *cthulhu7.Skills {
+	_, skills, _ := s.load(charID)
+	return skills
+}
+
+// GetMemo returns a memo value
+func (s *Store) GetMemo(charID, memoID string) string {
+	_, _, memos := s.load(charID)
+	return memos[memoID]
+}
+
+// SetMemo sets a memo value
+func (s *Store) SetMemo(charID, memoID, value string) bool {
+	_, _, memos := s.load(charID)
+	if memos[memoID] == value {
+		return false
+	}
+	s.coalesce.Write(charID, "memos."+memoID, value)
+	return true
+}
+
+// SetVariableBase sets a characteristic base within its range
+func (s *Store) SetVariableBase(charID, key string, value int) error {
+	status, _, _ := s.load(charID)
+	if err := status.SetVariableBase(key, value); err != nil {
+		return err
+	}
+	return s.coalesce.Write(charID, "status.variables."+key+".base", value)
+}
+
+// AdjustVariableBase changes a characteristic base by delta, clamped to its range
+func (s *Store) AdjustVariableBase(charID, key string, delta int) error {
+	status, _, _ := s.load(charID)
+	v, ok := status.Variables[key]
+	if !ok {
+		return fmt.Errorf("unknown characteristic %q", key)
+	}
+	return s.SetVariableBase(charID, key, min(max(v.Base+delta, v.Min), v.Max))
+}
+
+// SetLuck sets 幸運 (0-99)
+func (s *Store) SetLuck(charID string, value int) error {
+	if value < 0 || value > 99 {
+		return fmt.Errorf("luck must be between 0 and 99")
+	}
+	return s.coalesce.Write(charID, "status.luck", value)
+}
+
+// SetAge sets the character's age (15-99)
+func (s *Store) SetAge(charID string, value int) error {
+	if value < 15 || value > 99 {
+		return fmt.Errorf("age must be between 15 and 99")
+	}
+	return s.coalesce.Write(charID, "status.age", value)
+}
+
+// SetParameter sets a parameter (HP, MP, SAN) override
+func (s *Store) SetParameter(charID, key string, value int) error {
+	status, _, _ := s.load(charID)
+	if _, ok := status.Parameters[key]; !ok {
+		return fmt.Errorf("unknown parameter %q", key)
+	}
+	return s.coalesce.Write(charID, "status.parameters."+key, max(value, 0))
+}
+
+// AdjustParameter changes a parameter by delta from its effective value
+func (s *Store) AdjustParameter(charID, key string, delta int) error {
+	status, _, _ := s.load(charID)
+	if _, ok := status.Parameters[key]; !ok {
+		return fmt.Errorf("unknown parameter %q", key)
+	}
+	return s.coalesce.Write(charID, "status.parameters."+key, max(status.EffectiveParameter(key)+delta, 0))
+}
+
+// SetJobFormula selects the 職業P formula
+func (s *Store) SetJobFormula(charID, formula string) error {
+	if !slices.Contains(cthulhu7.JobFormulas, formula) {
+		return fmt.Errorf("unknown job formula %q", formula)
+	}
+	return s.coalesce.Write(charID, "status.jobFormula", formula)
+}
+
+// RollVariables rolls every characteristic and 幸運 in one batch
+func (s *Store) RollVariables(charID string) error {
+	status, _, _ := s.load(charID)
+	ops := make([]coalesce.Op, 0, len(cthulhu7.VariableOrder)+1)
+	for _, key := range cthulhu7.VariableOrder {
+		value, err := cthulhu7.RollVariable(s.roller, key, status.Variables[key])
+		if err != nil {
+			return err
+		}
+		ops = append(ops, coalesce.Op{Path: "status.variables." + key + ".base", Value: value})
+	}
+	luck, err := s.roller.Roll(cthulhu7.LuckDice)
+	if err != nil {
+		return fmt.Errorf("roll luck: %w", err)
+	}
+	ops = append(ops, coalesce.Op{Path: "status.luck", Value: luck.Total})
+	return s.coalesce.WriteBatch(charID, ops)
+}
+
+// SetSkillExtra sets extra skill points, which cannot go below zero
+func (s *Store) SetSkillExtra(charID string, extra cthulhu7.SkillExtra) error {
+	extra.Job, extra.Hobby = max(extra.Job, 0), max(extra.Hobby, 0)
+	return s.coalesce.Write(charID, "skills.extra", extra)
+}
+
+// GetSkill returns a built-in skill
+func (s *Store) GetSkill(charID, key string) (cthulhu7.Skill, bool) {
+	_, skills, _ := s.load(charID)
+	return skills.Skill(key)
+}
+
+// UpdateSkill stores a built-in skill
+func (s *Store) UpdateSkill(charID, key string, skill cthulhu7.Skill) error {
+	d, ok := cthulhu7.SkillDefByKey(key)
+	if !ok {
+		return fmt.Errorf("unknown skill %q", key)
+	}
+	return s.coalesce.Write(charID, "skills.categories."+string(d.Category)+".skills."+key, skill)
+}
+
+// AdjustSkill changes a field (job, hobby, growth) of a single skill
+func (s *Store) AdjustSkill(charID, key, field string, delta int) error {
+	skill, ok := s.GetSkill(charID, key)
+	if !ok || !skill.IsSingle() {
+		return fmt.Errorf("unknown single skill %q", key)
+	}
+	if err := skill.Single.Adjust(field, delta); err != nil {
+		return err
+	}
+	return s.UpdateSkill(charID, key, skill)
+}
+
+// multiSkill returns a multi skill, checking the genre index when index >= 0
+func (s *Store) multiSkill(charID, key string, index int) (cthulhu7.Skill, error) {
+	skill, ok := s.GetSkill(charID, key)
+	if !ok || !skill.IsMulti() {
+		return cthulhu7.Skill{}, fmt.Errorf("unknown multi skill %q", key)
+	}
+	if index >= len(skill.Multi.Genres) {
+		return cthulhu7.Skill{}, fmt.Errorf("%s has no genre %d", key, index)
+	}
+	return skill, nil
+}
+
+// AddGenre appends an empty genre to a multi skill
+func (s *Store) AddGenre(charID, key string) error {
+	skill, err := s.multiSkill(charID, key, -1)
+	if err != nil {
+		return err
+	}
+	skill.Multi.Genres = append(skill.Multi.Genres, cthulhu7.SkillGenre{})
+	return s.UpdateSkill(charID, key, skill)
+}
+
+// DeleteGenre removes a genre from a multi skill
+func (s *Store) DeleteGenre(charID, key string, index int) error {
+	if index < 0 {
+		return fmt.Errorf("invalid genre index %d", index)
+	}
+	skill, err := s.multiSkill(charID, key, index)
+	if err != nil {
+		return err
+	}
+	skill.Multi.Genres = slices.Delete(skill.Multi.Genres, index, index+1)
+	return s.UpdateSkill(charID, key, skill)
+}
+
+// SetGenreLabel renames a genre, which can change its initial value
+func (s *Store) SetGenreLabel(charID, key string, index int, label string) error {
+	if index < 0 {
+		return fmt.Errorf("invalid genre index %d", index)
+	}
+	skill, err := s.multiSkill(charID, key, index)
+	if err != nil {
+		return err
+	}
+	skill.Multi.Genres[index].Label = label
+	return s.UpdateSkill(charID, key, skill)
+}
+
+// AdjustGenre changes a field (job, hobby, growth) of a genre
+func (s *Store) AdjustGenre(charID, key string, index int, field string, delta int) error {
+	if index < 0 {
+		return fmt.Errorf("invalid genre index %d", index)
+	}
+	skill, err := s.multiSkill(charID, key, index)
+	if err != nil {
+		return err
+	}
+	if err := skill.Multi.Genres[index].Adjust(field, delta); err != nil {
+		return err
+	}
+	return s.UpdateSkill(charID, key, skill)
+}
+
+// AddCustomSkill appends an empty custom skill
+func (s *Store) AddCustomSkill(charID string) error {
+	_, skills, _ := s.load(charID)
+	return s.coalesce.Write(charID, "skills.custom", append(skills.Custom, cthulhu7.CustomSkill{}))
+}
+
+// DeleteCustomSkill removes a custom skill
+func (s *Store) DeleteCustomSkill(charID string, index int) error {
+	_, skills, _ := s.load(charID)
+	if index < 0 || index >= len(skills.Custom) {
+		return fmt.Errorf("no custom skill %d", index)
+	}
+	return s.coalesce.Write(charID, "skills.custom", slices.Delete(skills.Custom, index, index+1))
+}
+
+// SetCustomSkillName renames a custom skill
+func (s *Store) SetCustomSkillName(charID string, index int, name string) error {
+	_, skills, _ := s.load(charID)
+	if index < 0 || index >= len(skills.Custom) {
+		return fmt.Errorf("no custom skill %d", index)
+	}
+	skills.Custom[index].Name = name
+	return s.coalesce.Write(charID, "skills.custom", skills.Custom)
+}
+
+// AdjustCustomSkill changes a field (job, hobby, growth) of a custom skill
+func (s *Store) AdjustCustomSkill(charID string, index int, field string, delta int) error {
+	_, skills, _ := s.load(charID)
+	if index < 0 || index >= len(skills.Custom) {
+		return fmt.Errorf("no custom skill %d", index)
+	}
+	if err := skills.Custom[index].Adjust(field, delta); err != nil {
+		return err
+	}
+	return s.coalesce.Write(charID, "skills.custom", skills.Custom)
+}
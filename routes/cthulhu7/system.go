@@ -0,0 +1,65 @@
+package cthulhu7
+
+import (
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/routes/gamesystem"
+	"charaxiv/storage/coalesce"
+	"charaxiv/systems/cthulhu7"
+	"charaxiv/templates/shared"
+)
+
+// DefaultDocument is the character served at the system root
+const DefaultDocument = "demo-cthulhu7"
+
+// documentFields are the top-level fields of a stored character
+var documentFields = []gamesystem.Field{
+	gamesystem.FieldOf[storedStatus]("status"),
+	gamesystem.FieldOf[storedSkills]("skills"),
+	gamesystem.FieldOf[map[string]string]("memos"),
+}
+
+// System registers CoC 7th edition with the application router
+type System struct {
+	*gamesystem.Documents
+	store *Store
+}
+
+var _ gamesystem.GameSystem = (*System)(nil)
+
+// NewSystem creates the cthulhu7 game system backed by coalesce storage
+func NewSystem(cs *coalesce.Store) *System {
+	return &System{Documents: gamesystem.NewDocuments(cs, DefaultDocument, documentFields...), store: NewStore(cs)}
+}
+
+// ID returns the URL prefix
+func (s *System) ID() string { return "cthulhu7" }
+
+// Name returns the display name
+func (s *System) Name() string { return "クトゥルフ神話TRPG 第7版" }
+
+// Router builds the system's routes
+func (s *System) Router() chi.Router { return Routes(s.store) }
+
+// Summary describes a character by parameters, 幸運 and damage bonus
+func (s *System) Summary(charID string) shared.CharacterSummary {
+	status := s.store.GetStatus(charID)
+	out := shared.CharacterSummary{
+		System:     s.ID(),
+		SystemName: s.Name(),
+		ID:         charID,
+		URL:        "/" + s.ID() + "/",
+		Memo:       s.store.GetMemo(charID, "public-memo"),
+	}
+	for _, key := range cthulhu7.ParameterOrder {
+		out.Stats = append(out.Stats, shared.SummaryStat{Label: key, Value: strconv.Itoa(status.EffectiveParameter(key))})
+	}
+	_, db := status.BuildAndDamageBonus()
+	out.Stats = append(out.Stats,
+		shared.SummaryStat{Label: "幸運", Value: strconv.Itoa(status.Luck)},
+		shared.SummaryStat{Label: "DB", Value: db},
+	)
+	return out
+}
@@ -0,0 +1,206 @@
+package emoklore
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/systems/emoklore"
+	"charaxiv/templates/components"
+	"charaxiv/templates/pages"
+	"charaxiv/templates/shared"
+)
+
+// html wraps a templ.Component handler, setting the Content-Type header.
+func html(c func(r *http.Request) templ.Component) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		c(r).Render(r.Context(), w)
+	}
+}
+
+// buildPageContext creates a PageContext with memos loaded from the store
+func buildPageContext(store *Store, charID, basePath string) shared.PageContext {
+	ctx := shared.NewPageContext()
+	ctx.BasePath = basePath
+	memoIDs := []string{"public-memo", "secret-memo", "scenario-public-memo", "scenario-secret-memo"}
+	for _, id := range memoIDs {
+		ctx.Memos[id] = store.GetMemo(charID, id)
+	}
+	return ctx
+}
+
+// buildSheetState loads the character and converts it to template types
+func buildSheetState(store *Store, charID, basePath string) shared.EmokloreSheetState {
+	pc := buildPageContext(store, charID, basePath)
+	return emoklore.BuildSheetState(pc, store.GetStatus(charID), store.GetSkills(charID))
+}
+
+// sheetFragments renders the status and skills panels for OOB swaps.
+// Abilities feed both parameters and every skill check, so mutations
+// refresh both.
+func sheetFragments(store *Store, charID, basePath string) templ.Component {
+	return components.EmokloreSheetFragments(buildSheetState(store, charID, basePath))
+}
+
+// intParam parses an integer URL parameter, defaulting to -1
+func intParam(r *http.Request, name string) int {
+	n := -1
+	fmt.Sscanf(chi.URLParam(r, name), "%d", &n)
+	return n
+}
+
+// delta parses the delta query parameter of an adjust request
+func delta(r *http.Request) int {
+	d := 0
+	fmt.Sscanf(r.URL.Query().Get("delta"), "%d", &d)
+	return d
+}
+
+// Routes returns a chi.Router with all emoklore-specific routes.
+func Routes(store *Store) chi.Router {
+	r := chi.NewRouter()
+
+	// For now, use a fixed character ID until we have proper routing
+	const charID = DefaultDocument
+	const basePath = "/emoklore"
+
+	// Character sheet
+	r.Get("/", html(func(r *http.Request) templ.Component {
+		return pages.EmokloreSheet(buildSheetState(store, charID, basePath))
+	}))
+
+	// Preview mode toggle - returns targeted fragments with OOB swaps
+	r.Post("/api/preview/on", html(func(r *http.Request) templ.Component {
+		ctx := buildPageContext(store, charID, basePath)
+		ctx.Preview = true
+		return pages.EmoklorePreviewModeFragments(ctx)
+	}))
+
+	r.Post("/api/preview/off", html(func(r *http.Request) templ.Component {
+		ctx := buildPageContext(store, charID, basePath)
+		ctx.Preview = false
+		return pages.EmoklorePreviewModeFragments(ctx)
+	}))
+
+	// Memo update
+	r.Post("/api/memo/{id}/set", func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		r.ParseForm()
+		store.SetMemo(charID, id, r.FormValue(id))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Status value set (direct value from input): abilities, 共鳴 and parameters
+	r.Post("/api/status/{key}/set", func(w http.ResponseWriter, r *http.Request) {
+		key := chi.URLParam(r, "key")
+		r.ParseForm()
+		var value int
+		if _, err := fmt.Sscanf(r.FormValue(strings.ReplaceAll(key, "-", "_")), "%d", &value); err != nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// Rejected values re-render the panels so the input reverts
+		switch {
+		case key == "status-共鳴":
+			store.SetResonance(charID, value)
+		case strings.HasPrefix(key, "param-"):
+			store.SetParameter(charID, strings.TrimPrefix(key, "param-"), value)
+		case strings.HasPrefix(key, "status-"):
+			if _, ok := store.GetStatus(charID).Abilities[strings.TrimPrefix(key, "status-")]; !ok {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			store.SetAbility(charID, strings.TrimPrefix(key, "status-"), value)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		sheetFragments(store, charID, basePath).Render(r.Context(), w)
+	})
+
+	// Status value adjustment: abilities, 共鳴 and parameters
+	r.Post("/api/status/{key}/adjust", html(func(r *http.Request) templ.Component {
+		key := chi.URLParam(r, "key")
+		d := delta(r)
+
+		// Out-of-range adjustments still re-render so the input shows the stored value
+		switch {
+		case key == "status-共鳴":
+			store.SetResonance(charID, min(max(store.GetStatus(charID).Resonance+d, emoklore.ResonanceMin), emoklore.ResonanceMax))
+		case strings.HasPrefix(key, "param-"):
+			if err := store.AdjustParameter(charID, strings.TrimPrefix(key, "param-"), d); err != nil {
+				return shared.Empty()
+			}
+		default:
+			if err := store.AdjustAbility(charID, strings.TrimPrefix(key, "status-"), d); err != nil {
+				return shared.Empty()
+			}
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Resonance emotion update
+	r.Post("/api/status/emotion/{kind}/set", func(w http.ResponseWriter, r *http.Request) {
+		kind := chi.URLParam(r, "kind")
+		r.ParseForm()
+		store.SetEmotion(charID, kind, r.FormValue(kind))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Custom skill: add
+	r.Post("/api/skill/custom/add", html(func(r *http.Request) templ.Component {
+		if err := store.AddCustomSkill(charID); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Custom skill: delete
+	r.Post("/api/skill/custom/{index}/delete", html(func(r *http.Request) templ.Component {
+		if err := store.DeleteCustomSkill(charID, intParam(r, "index")); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Custom skill: name update
+	r.Post("/api/skill/custom/{index}/name", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		store.SetCustomSkillName(charID, intParam(r, "index"), r.FormValue("name"))
+		return shared.Empty()
+	}))
+
+	// Custom skill: change the ability it is checked with
+	r.Post("/api/skill/custom/{index}/ability", html(func(r *http.Request) templ.Component {
+		r.ParseForm()
+		if err := store.SetCustomSkillAbility(charID, intParam(r, "index"), r.FormValue("ability")); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Custom skill: level adjustment
+	r.Post("/api/skill/custom/{index}/level/adjust", html(func(r *http.Request) templ.Component {
+		if err := store.AdjustCustomSkillLevel(charID, intParam(r, "index"), delta(r)); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	// Skill level adjustment
+	r.Post("/api/skill/{key}/level/adjust", html(func(r *http.Request) templ.Component {
+		if err := store.AdjustSkillLevel(charID, chi.URLParam(r, "key"), delta(r)); err != nil {
+			return shared.Empty()
+		}
+		return sheetFragments(store, charID, basePath)
+	}))
+
+	return r
+}
@@ -0,0 +1,381 @@
+package emoklore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/routes/gamesystem"
+	"charaxiv/storage/coalesce"
+)
+
+// RouteTest defines a test case for a route
+type RouteTest struct {
+	Method       string
+	Route        string
+	Desc         string
+	TestURL      string       // Actual URL to test (with params filled in)
+	Form         url.Values   // Form data to send
+	Query        string       // Query string (e.g., "delta=1")
+	Setup        func(*Store) // Optional setup before test
+	WantCode     int
+	WantContains []string // Strings that should be in response body
+}
+
+// routeTests defines all routes with their test cases
+var routeTests = []RouteTest{
+	{
+		Method:       "GET",
+		Route:        "/emoklore/",
+		Desc:         "Sheet page",
+		TestURL:      "/emoklore/",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"CharaXiv", "/emoklore/api/", "text/html", "行動値", "共鳴感情", "1DM&lt;=3"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/preview/on",
+		Desc:         "Enable preview mode",
+		TestURL:      "/emoklore/api/preview/on",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"memo-group"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/preview/off",
+		Desc:         "Disable preview mode",
+		TestURL:      "/emoklore/api/preview/off",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"memo-group"},
+	},
+	{
+		Method:   "POST",
+		Route:    "/emoklore/api/memo/{id}/set",
+		Desc:     "Set memo",
+		TestURL:  "/emoklore/api/memo/public-memo/set",
+		Form:     url.Values{"public-memo": {"test memo"}},
+		WantCode: http.StatusNoContent,
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/status/{key}/set",
+		Desc:         "Set ability directly",
+		TestURL:      "/emoklore/api/status/status-身体/set",
+		Form:         url.Values{"status_身体": {"5"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "skills-panel", `placeholder="15"`, "1DM&lt;=5"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/status/{key}/set",
+		Desc:         "Set resonance directly",
+		TestURL:      "/emoklore/api/status/status-共鳴/set",
+		Form:         url.Values{"status_共鳴": {"4"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", `value="4"`},
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/status/{key}/set",
+		Desc:         "Set parameter directly",
+		TestURL:      "/emoklore/api/status/param-HP/set",
+		Form:         url.Values{"param_HP": {"7"}},
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", `value="7"`},
+	},
+	{
+		Method:   "POST",
+		Route:    "/emoklore/api/status/{key}/set",
+		Desc:     "Set unknown status",
+		TestURL:  "/emoklore/api/status/status-STR/set",
+		Form:     url.Values{"status_STR": {"5"}},
+		WantCode: http.StatusNoContent,
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/status/{key}/adjust",
+		Desc:         "Adjust ability",
+		TestURL:      "/emoklore/api/status/status-五感/adjust",
+		Query:        "delta=1",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", "残り 3"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/status/{key}/adjust",
+		Desc:         "Adjust parameter",
+		TestURL:      "/emoklore/api/status/param-MP/adjust",
+		Query:        "delta=-2",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"status-panel", `value="11"`},
+	},
+	{
+		Method:   "POST",
+		Route:    "/emoklore/api/status/emotion/{kind}/set",
+		Desc:     "Set emotion",
+		TestURL:  "/emoklore/api/status/emotion/surface/set",
+		Form:     url.Values{"surface": {"好奇心"}},
+		WantCode: http.StatusNoContent,
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/skill/{key}/level/adjust",
+		Desc:         "Adjust skill level",
+		TestURL:      "/emoklore/api/skill/知覚/level/adjust",
+		Query:        "delta=2",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "2DM&lt;=5", "残り 7"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/skill/custom/add",
+		Desc:         "Add custom skill",
+		TestURL:      "/emoklore/api/skill/custom/add",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "custom-0-level"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/skill/custom/{index}/delete",
+		Desc:         "Delete custom skill",
+		TestURL:      "/emoklore/api/skill/custom/0/delete",
+		Setup:        func(s *Store) { s.AddCustomSkill(DefaultDocument) },
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel"},
+	},
+	{
+		Method:   "POST",
+		Route:    "/emoklore/api/skill/custom/{index}/name",
+		Desc:     "Rename custom skill",
+		TestURL:  "/emoklore/api/skill/custom/0/name",
+		Form:     url.Values{"name": {"料理"}},
+		Setup:    func(s *Store) { s.AddCustomSkill(DefaultDocument) },
+		WantCode: http.StatusOK,
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/skill/custom/{index}/ability",
+		Desc:         "Change custom skill ability",
+		TestURL:      "/emoklore/api/skill/custom/0/ability",
+		Form:         url.Values{"ability": {"器用"}},
+		Setup:        func(s *Store) { s.AddCustomSkill(DefaultDocument) },
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", `value="器用" selected`},
+	},
+	{
+		Method:       "POST",
+		Route:        "/emoklore/api/skill/custom/{index}/level/adjust",
+		Desc:         "Adjust custom skill level",
+		TestURL:      "/emoklore/api/skill/custom/0/level/adjust",
+		Query:        "delta=1",
+		Setup:        func(s *Store) { s.AddCustomSkill(DefaultDocument) },
+		WantCode:     http.StatusOK,
+		WantContains: []string{"skills-panel", "残り 9"},
+	},
+}
+
+// TestRoutes runs all route tests from the table
+func TestRoutes(t *testing.T) {
+	for _, tt := range routeTests {
+		t.Run(tt.Desc, func(t *testing.T) {
+			r, store, cleanup := setupTestRouter(t)
+			defer cleanup()
+
+			if tt.Setup != nil {
+				tt.Setup(store)
+			}
+
+			testURL := tt.TestURL
+			if tt.Query != "" {
+				testURL += "?" + tt.Query
+			}
+
+			var req *http.Request
+			if tt.Form != nil {
+				req = httptest.NewRequest(tt.Method, testURL, strings.NewReader(tt.Form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			} else {
+				req = httptest.NewRequest(tt.Method, testURL, nil)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.WantCode {
+				t.Errorf("Expected status %d, got %d", tt.WantCode, w.Code)
+			}
+
+			fullResponse := w.Body.String() + w.Header().Get("Content-Type")
+			for _, want := range tt.WantContains {
+				if !strings.Contains(fullResponse, want) {
+					t.Errorf("Expected response to contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+// TestAllRoutesHaveTests verifies every registered route has a test case
+func TestAllRoutesHaveTests(t *testing.T) {
+	r, _, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	testedRoutes := make(map[string]bool)
+	for _, tt := range routeTests {
+		testedRoutes[tt.Method+" "+tt.Route] = true
+	}
+
+	registeredSet := make(map[string]bool)
+	chi.Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		key := method + " " + route
+		registeredSet[key] = true
+		if !testedRoutes[key] {
+			t.Errorf("Route has no test: %s", key)
+		}
+		return nil
+	})
+
+	for _, tt := range routeTests {
+		key := tt.Method + " " + tt.Route
+		if !registeredSet[key] {
+			t.Errorf("Test exists for unregistered route: %s (%s)", key, tt.Desc)
+		}
+	}
+}
+
+// setupTestRouter creates a test router with a fresh store.
+func setupTestRouter(t *testing.T) (chi.Router, *Store, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "emoklore-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+
+	backend, err := coalesce.NewDiskBackend(filepath.Join(tmpDir, "characters"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create disk backend: %v", err)
+	}
+
+	cs, err := coalesce.New(coalesce.Config{
+		DBPath:  filepath.Join(tmpDir, "buffer.db"),
+		Backend: backend,
+	})
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create coalesce store: %v", err)
+	}
+
+	store := NewStore(cs)
+	r := chi.NewRouter()
+	r.Mount("/emoklore", Routes(store))
+
+	cleanup := func() {
+		cs.Close()
+		os.RemoveAll(tmpDir)
+	}
+
+	return r, store, cleanup
+}
+
+// post sends a POST request with optional form values
+func post(r chi.Router, target string, form url.Values) *httptest.ResponseRecorder {
+	var req *http.Request
+	if form != nil {
+		req = httptest.NewRequest("POST", target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = httptest.NewRequest("POST", target, nil)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestAbilityAdjustClampsToRange tests that adjustments stop at 1 and 6
+func TestAbilityAdjustClampsToRange(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	post(r, "/emoklore/api/status/status-魅力/adjust?delta=10", nil)
+	if got := store.GetStatus(DefaultDocument).Abilities["魅力"]; got != 6 {
+		t.Errorf("Expected 魅力 to stop at 6, got %d", got)
+	}
+
+	w := post(r, "/emoklore/api/status/status-運勢/set", url.Values{"status_運勢": {"0"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected rejected value to re-render, got %d", w.Code)
+	}
+	if got := store.GetStatus(DefaultDocument).Abilities["運勢"]; got != 3 {
+		t.Errorf("Expected 運勢 to stay 3, got %d", got)
+	}
+}
+
+// TestSkillLevelClampsAndCosts tests that levels stop at 3 and cost 1, 3 and 6 points
+func TestSkillLevelClampsAndCosts(t *testing.T) {
+	r, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+
+	post(r, "/emoklore/api/skill/交渉/level/adjust?delta=5", nil)
+	skills := store.GetSkills(DefaultDocument)
+	if got := skills.Levels["交渉"]; got != 3 {
+		t.Fatalf("Expected 交渉 to stop at level 3, got %d", got)
+	}
+	if got := skills.RemainingPoints(); got != 4 {
+		t.Errorf("Expected 4 skill points left, got %d", got)
+	}
+
+	w := post(r, "/emoklore/api/skill/料理/level/adjust?delta=1", nil)
+	if w.Body.Len() != 0 {
+		t.Error("Expected an unknown skill to be rejected")
+	}
+}
+
+// TestSystemExportImport tests that an exported character imports back and stray fields are rejected
+func TestSystemExportImport(t *testing.T) {
+	_, store, cleanup := setupTestRouter(t)
+	defer cleanup()
+	sys := &System{Documents: gamesystem.NewDocuments(store.coalesce, DefaultDocument, documentFields...), store: store}
+
+	store.SetAbility(DefaultDocument, "器用", 5)
+	store.SetEmotion(DefaultDocument, "roots", "喪失")
+	store.AdjustSkillLevel(DefaultDocument, "隠密", 2)
+	data, err := sys.Export(DefaultDocument)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if err := sys.Import("copy", data); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if got := store.GetSkills("copy").Levels["隠密"]; got != 2 {
+		t.Errorf("Expected imported 隠密 level 2, got %d", got)
+	}
+	summary := sys.Summary("copy")
+	if summary.Title != "喪失" {
+		t.Errorf("Expected the roots emotion as title, got %q", summary.Title)
+	}
+	if got := summary.Stats[2]; got.Label != "行動値" || got.Value != "8" {
+		t.Errorf("Expected 行動値 8 in the summary, got %v", got)
+	}
+
+	if err := sys.Import("copy", map[string]any{"weapons": []any{}}); err == nil {
+		t.Error("Expected an unknown field to be rejected")
+	}
+	if err := sys.Import("copy", map[string]any{"status": "x"}); err == nil {
+		t.Error("Expected a malformed status to be rejected")
+	}
+	if err := sys.Import("copy", map[string]any{"skills": map[string]any{"custom": 1}}); err == nil {
+		t.Error("Expected malformed skills to be rejected")
+	}
+	if got := store.GetSkills("copy").Levels["隠密"]; got != 2 {
+		t.Errorf("Expected a rejected import to leave the character unchanged, got 隠密 level %d", got)
+	}
+}
@@ -0,0 +1,243 @@
+package emoklore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"charaxiv/storage/coalesce"
+	"charaxiv/systems/emoklore"
+)
+
+// Store wraps coalesce.Store with emoklore-specific typed access.
+type Store struct {
+	coalesce *coalesce.Store
+}
+
+// NewStore creates a new emoklore store backed by coalesce storage.
+func NewStore(c *coalesce.Store) *Store {
+	return &Store{coalesce: c}
+}
+
+// view reads raw character data from coalesce, merged with pending writes.
+func (s *Store) view(charID string) map[string]any {
+	data, err := s.coalesce.View(context.Background(), charID)
+	if err != nil {
+		return map[string]any{}
+	}
+	return data
+}
+
+// decode converts a raw JSON value into a typed value via a JSON round trip
+func decode(value any, out any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// storedStatus is the status as stored, where missing values keep defaults
+type storedStatus struct {
+	Abilities  map[string]int    `json:"abilities"`
+	Parameters map[string]*int   `json:"parameters"`
+	Resonance  *int              `json:"resonance"`
+	Emotions   emoklore.Emotions `json:"emotions"`
+}
+
+// load reads typed character data, starting from defaults
+func (s *Store) load(charID string) (*emoklore.Status, *emoklore.Skills, map[string]string) {
+	data := s.view(charID)
+
+	status := emoklore.NewStatus()
+	skills := emoklore.NewSkills()
+	memos := make(map[string]string)
+
+	var st storedStatus
+	if raw, ok := data["status"]; ok && decode(raw, &st) == nil {
+		for key, value := range st.Abilities {
+			status.SetAbility(key, value)
+		}
+		for key, val := range st.Parameters {
+			if _, ok := status.Parameters[key]; ok {
+				status.Parameters[key] = val
+			}
+		}
+		if st.Resonance != nil {
+			status.SetResonance(*st.Resonance)
+		}
+		status.Emotions = st.Emotions
+	}
+
+	var sk emoklore.Skills
+	if raw, ok := data["skills"]; ok && decode(raw, &sk) == nil {
+		for key, level := range sk.Levels {
+			skills.SetLevel(key, level)
+		}
+		for _, cs := range sk.Custom {
+			if !slices.Contains(emoklore.AbilityOrder, cs.Ability) {
+				cs.Ability = emoklore.AbilityOrder[0]
+			}
+			skills.Custom = append(skills.Custom, cs)
+		}
+	}
+
+	if memosData, ok := data["memos"].(map[string]any); ok {
+		for k, v := range memosData {
+			if str, ok := v.(string); ok {
+				memos[k] = str
+			}
+		}
+	}
+
+	return status, skills, memos
+}
+
+// GetStatus returns the status for a character
+func (s *Store) GetStatus(charID string) *emoklore.Status {
+	status, _, _ := s.load(charID)
+	return status
+}
+
+// GetSkills returns the skills for a character
+func (s *Store) GetSkills(charID string) *emoklore.Skills {
+	_, skills, _ := s.load(charID)
+	return skills
+}
+
+// GetMemo returns a memo value
+func (s *Store) GetMemo(charID, memoID string) string {
+	_, _, memos := s.load(charID)
+	return memos[memoID]
+}
+
+// SetMemo sets a memo value
+func (s *Store) SetMemo(charID, memoID, value string) bool {
+	_, _, memos := s.load(charID)
+	if memos[memoID] == value {
+		return false
+	}
+	s.coalesce.Write(charID, "memos."+memoID, value)
+	return true
+}
+
+// SetAbility sets an ability within its range
+func (s *Store) SetAbility(charID, key string, value int) error {
+	status, _, _ := s.load(charID)
+	if err := status.SetAbility(key, value); err != nil {
+		return err
+	}
+	return s.coalesce.Write(charID, "status.abilities."+key, value)
+}
+
+// AdjustAbility changes an ability by delta, clamped to its range
+func (s *Store) AdjustAbility(charID, key string, delta int) error {
+	status, _, _ := s.load(charID)
+	value, ok := status.Abilities[key]
+	if !ok {
+		return fmt.Errorf("unknown ability %q", key)
+	}
+	return s.SetAbility(charID, key, min(max(value+delta, emoklore.AbilityMin), emoklore.AbilityMax))
+}
+
+// SetResonance sets 共鳴 within its range
+func (s *Store) SetResonance(charID string, value int) error {
+	status, _, _ := s.load(charID)
+	if err := status.SetResonance(value); err != nil {
+		return err
+	}
+	return s.coalesce.Write(charID, "status.resonance", value)
+}
+
+// SetParameter sets a current parameter (HP, MP), which cannot go below zero
+func (s *Store) SetParameter(charID, key string, value int) error {
+	status, _, _ := s.load(charID)
+	if _, ok := status.Parameters[key]; !ok {
+		return fmt.Errorf("unknown parameter %q", key)
+	}
+	return s.coalesce.Write(charID, "status.parameters."+key, max(value, 0))
+}
+
+// AdjustParameter changes a parameter by delta from its current value
+func (s *Store) AdjustParameter(charID, key string, delta int) error {
+	status, _, _ := s.load(charID)
+	if _, ok := status.Parameters[key]; !ok {
+		return fmt.Errorf("unknown parameter %q", key)
+	}
+	return s.SetParameter(charID, key, status.EffectiveParameter(key)+delta)
+}
+
+// SetEmotion sets one resonance emotion
+func (s *Store) SetEmotion(charID, kind, value string) error {
+	var e emoklore.Emotions
+	if err := e.Set(kind, value); err != nil {
+		return err
+	}
+	return s.coalesce.Write(charID, "status.emotions."+kind, value)
+}
+
+// AdjustSkillLevel changes a built-in skill's level by delta, clamped to 0-3
+func (s *Store) AdjustSkillLevel(charID, key string, delta int) error {
+	_, skills, _ := s.load(charID)
+	level := min(max(skills.Levels[key]+delta, 0), emoklore.SkillLevelMax)
+	if err := skills.SetLevel(key, level); err != nil {
+		return err
+	}
+	return s.coalesce.Write(charID, "skills.levels."+key, level)
+}
+
+// updateCustomSkill applies fn to a custom skill and stores the list
+func (s *Store) updateCustomSkill(charID string, index int, fn func(*emoklore.CustomSkill) error) error {
+	_, skills, _ := s.load(charID)
+	if index < 0 || index >= len(skills.Custom) {
+		return fmt.Errorf("no custom skill %d", index)
+	}
+	if err := fn(&skills.Custom[index]); err != nil {
+		return err
+	}
+	return s.coalesce.Write(charID, "skills.custom", skills.Custom)
+}
+
+// AddCustomSkill appends an unlearned custom skill checked with 身体
+func (s *Store) AddCustomSkill(charID string) error {
+	_, skills, _ := s.load(charID)
+	cs := emoklore.CustomSkill{Ability: emoklore.AbilityOrder[0]}
+	return s.coalesce.Write(charID, "skills.custom", append(skills.Custom, cs))
+}
+
+// DeleteCustomSkill removes a custom skill
+func (s *Store) DeleteCustomSkill(charID string, index int) error {
+	_, skills, _ := s.load(charID)
+	if index < 0 || index >= len(skills.Custom) {
+		return fmt.Errorf("no custom skill %d", index)
+	}
+	return s.coalesce.Write(charID, "skills.custom", slices.Delete(skills.Custom, index, index+1))
+}
+
+// SetCustomSkillName renames a custom skill
+func (s *Store) SetCustomSkillName(charID string, index int, name string) error {
+	return s.updateCustomSkill(charID, index, func(cs *emoklore.CustomSkill) error {
+		cs.Name = name
+		return nil
+	})
+}
+
+// SetCustomSkillAbility changes the ability a custom skill is checked with
+func (s *Store) SetCustomSkillAbility(charID string, index int, ability string) error {
+	if !slices.Contains(emoklore.AbilityOrder, ability) {
+		return fmt.Errorf("unknown ability %q", ability)
+	}
+	return s.updateCustomSkill(charID, index, func(cs *emoklore.CustomSkill) error {
+		cs.Ability = ability
+		return nil
+	})
+}
+
+// AdjustCustomSkillLevel changes a custom skill's level by delta, clamped to 0-3
+func (s *Store) AdjustCustomSkillLevel(charID string, index int, delta int) error {
+	return s.updateCustomSkill(charID, index, func(cs *emoklore.CustomSkill) error {
+		cs.Level = min(max(cs.Level+delta, 0), emoklore.SkillLevelMax)
+		return nil
+	})
+}
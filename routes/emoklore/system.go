@@ -0,0 +1,65 @@
+package emoklore
+
+import (
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/routes/gamesystem"
+	"charaxiv/storage/coalesce"
+	"charaxiv/systems/emoklore"
+	"charaxiv/templates/shared"
+)
+
+// DefaultDocument is the character served at the system root
+const DefaultDocument = "demo-emoklore"
+
+// documentFields are the top-level fields of a stored character
+var documentFields = []gamesystem.Field{
+	gamesystem.FieldOf[storedStatus]("status"),
+	gamesystem.FieldOf[emoklore.Skills]("skills"),
+	gamesystem.FieldOf[map[string]string]("memos"),
+}
+
+// System registers Emoklore with the application router
+type System struct {
+	*gamesystem.Documents
+	store *Store
+}
+
+var _ gamesystem.GameSystem = (*System)(nil)
+
+// NewSystem creates the emoklore game system backed by coalesce storage
+func NewSystem(cs *coalesce.Store) *System {
+	return &System{Documents: gamesystem.NewDocuments(cs, DefaultDocument, documentFields...), store: NewStore(cs)}
+}
+
+// ID returns the URL prefix
+func (s *System) ID() string { return "emoklore" }
+
+// Name returns the display name
+func (s *System) Name() string { return "エモクロアTRPG" }
+
+// Router builds the system's routes
+func (s *System) Router() chi.Router { return Routes(s.store) }
+
+// Summary describes a character by parameters, 行動値 and 共鳴
+func (s *System) Summary(charID string) shared.CharacterSummary {
+	status := s.store.GetStatus(charID)
+	out := shared.CharacterSummary{
+		System:     s.ID(),
+		SystemName: s.Name(),
+		ID:         charID,
+		URL:        "/" + s.ID() + "/",
+		Title:      status.Emotions.Roots,
+		Memo:       s.store.GetMemo(charID, "public-memo"),
+	}
+	for _, key := range emoklore.ParameterOrder {
+		out.Stats = append(out.Stats, shared.SummaryStat{Label: key, Value: strconv.Itoa(status.EffectiveParameter(key))})
+	}
+	out.Stats = append(out.Stats,
+		shared.SummaryStat{Label: "行動値", Value: strconv.Itoa(status.Initiative())},
+		shared.SummaryStat{Label: "共鳴", Value: strconv.Itoa(status.Resonance)},
+	)
+	return out
+}
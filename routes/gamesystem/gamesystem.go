@@ -0,0 +1,150 @@
+// Package gamesystem defines the interface a ruleset implements to plug into
+// the application router, and the cross-system index, search, export and
+// import routes built on it.
+package gamesystem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/storage/coalesce"
+	"charaxiv/templates/shared"
+)
+
+// GameSystem is a ruleset with its own store, routes and templates
+type GameSystem interface {
+	// ID is the URL prefix and export tag, e.g. "cthulhu6"
+	ID() string
+	// Name is the display name, e.g. "クトゥルフ神話TRPG"
+	Name() string
+	// DefaultDocument is the character ID served at the system's root
+	DefaultDocument() string
+	// Router builds the system's routes, mounted at "/" + ID()
+	Router() chi.Router
+	// Summary describes a character for listings and search
+	Summary(charID string) shared.CharacterSummary
+	// Export returns a character's stored data
+	Export(charID string) (map[string]any, error)
+	// Import replaces a character's stored data, rejecting data the system cannot read
+	Import(charID string, data map[string]any) error
+}
+
+// Registry holds the registered game systems in display order
+type Registry struct {
+	systems []GameSystem
+}
+
+// NewRegistry registers game systems in display order. It panics on an empty
+// or duplicate ID, since IDs are fixed at compile time.
+func NewRegistry(systems ...GameSystem) *Registry {
+	seen := make(map[string]bool, len(systems))
+	for _, sys := range systems {
+		if sys.ID() == "" || seen[sys.ID()] {
+			panic(fmt.Sprintf("gamesystem: empty or duplicate system ID %q", sys.ID()))
+		}
+		seen[sys.ID()] = true
+	}
+	return &Registry{systems: slices.Clone(systems)}
+}
+
+// Systems returns the registered systems in display order
+func (r *Registry) Systems() []GameSystem {
+	return r.systems
+}
+
+// Lookup returns the system with the given ID
+func (r *Registry) Lookup(id string) (GameSystem, bool) {
+	for _, sys := range r.systems {
+		if sys.ID() == id {
+			return sys, true
+		}
+	}
+	return nil, false
+}
+
+// Summaries returns the summary of every system's default document
+func (r *Registry) Summaries() []shared.CharacterSummary {
+	out := make([]shared.CharacterSummary, 0, len(r.systems))
+	for _, sys := range r.systems {
+		out = append(out, sys.Summary(sys.DefaultDocument()))
+	}
+	return out
+}
+
+// Document is the portable form of a character, tagged with its system
+type Document struct {
+	System string         `json:"system"`
+	Data   map[string]any `json:"data"`
+}
+
+// Field is a top-level field of a stored character. New returns a pointer to
+// the typed value the field is stored as, used to check imported data.
+type Field struct {
+	Key string
+	New func() any
+}
+
+// FieldOf declares a top-level field stored as a T
+func FieldOf[T any](key string) Field {
+	return Field{Key: key, New: func() any { return new(T) }}
+}
+
+// ReplaceOps builds the batch that replaces a character's stored data with
+// data. Fields missing from data are cleared; unknown fields, and values that
+// do not decode into their field's type, are rejected.
+func ReplaceOps(fields []Field, data map[string]any) ([]coalesce.Op, error) {
+	for key, value := range data {
+		i := slices.IndexFunc(fields, func(f Field) bool { return f.Key == key })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+		b, err := json.Marshal(value)
+		if err == nil {
+			err = json.Unmarshal(b, fields[i].New())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", key, err)
+		}
+	}
+	ops := make([]coalesce.Op, 0, len(fields))
+	for _, f := range fields {
+		ops = append(ops, coalesce.Op{Path: f.Key, Value: data[f.Key]})
+	}
+	return ops, nil
+}
+
+// Documents implements the storage half of GameSystem for a system keeping
+// its characters in coalesce storage. Systems embed it and supply the rest.
+type Documents struct {
+	store           *coalesce.Store
+	defaultDocument string
+	fields          []Field
+}
+
+// NewDocuments serves characters with the given top-level fields from cs.
+// defaultDocument is the character served at the system root until
+// characters have their own routes.
+func NewDocuments(cs *coalesce.Store, defaultDocument string, fields ...Field) *Documents {
+	return &Documents{store: cs, defaultDocument: defaultDocument, fields: fields}
+}
+
+// DefaultDocument returns the character served at the system root
+func (d *Documents) DefaultDocument() string { return d.defaultDocument }
+
+// Export returns a character's stored data
+func (d *Documents) Export(charID string) (map[string]any, error) {
+	return d.store.View(context.Background(), charID)
+}
+
+// Import replaces a character's stored data
+func (d *Documents) Import(charID string, data map[string]any) error {
+	ops, err := ReplaceOps(d.fields, data)
+	if err != nil {
+		return err
+	}
+	return d.store.WriteBatch(charID, ops)
+}
@@ -0,0 +1,108 @@
+package gamesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/templates/pages"
+	"charaxiv/templates/shared"
+)
+
+// maxImportSize caps uploaded documents
+const maxImportSize = 1 << 20
+
+// html wraps a templ.Component handler, setting the Content-Type header.
+func html(c func(r *http.Request) templ.Component) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		c(r).Render(r.Context(), w)
+	}
+}
+
+// Search returns the summaries matching every whitespace-separated term of
+// query, case-insensitively, against the system name, title, stats and memo
+func Search(summaries []shared.CharacterSummary, query string) []shared.CharacterSummary {
+	terms := strings.Fields(strings.ToLower(query))
+	out := make([]shared.CharacterSummary, 0, len(summaries))
+	for _, s := range summaries {
+		text := []string{s.SystemName, s.Title, s.Memo}
+		for _, stat := range s.Stats {
+			text = append(text, stat.Label+" "+stat.Value)
+		}
+		haystack := strings.ToLower(strings.Join(text, "\n"))
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(haystack, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Routes returns the cross-system routes: the character index, search,
+// export and import.
+func Routes(reg *Registry) chi.Router {
+	r := chi.NewRouter()
+
+	// Character index
+	r.Get("/", html(func(r *http.Request) templ.Component {
+		return pages.Index(reg.Summaries())
+	}))
+
+	// Search characters across systems
+	r.Get("/search", html(func(r *http.Request) templ.Component {
+		return pages.CharacterList(Search(reg.Summaries(), r.URL.Query().Get("q")))
+	}))
+
+	// Export a system's default document as JSON
+	r.Get("/export/{system}", func(w http.ResponseWriter, r *http.Request) {
+		sys, ok := reg.Lookup(chi.URLParam(r, "system"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data, err := sys.Export(sys.DefaultDocument())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.json"`, sys.ID(), sys.DefaultDocument()))
+		json.NewEncoder(w).Encode(Document{System: sys.ID(), Data: data})
+	})
+
+	// Import an exported document into its system's default document
+	r.Post("/import", html(func(r *http.Request) templ.Component {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return pages.ImportResult("ファイルを選択してください", "")
+		}
+		defer file.Close()
+
+		var doc Document
+		if err := json.NewDecoder(io.LimitReader(file, maxImportSize)).Decode(&doc); err != nil {
+			return pages.ImportResult("JSONを読み込めません", "")
+		}
+		sys, ok := reg.Lookup(doc.System)
+		if !ok {
+			return pages.ImportResult(fmt.Sprintf("未対応のシステムです: %q", doc.System), "")
+		}
+		if err := sys.Import(sys.DefaultDocument(), doc.Data); err != nil {
+			return pages.ImportResult("読み込めません: "+err.Error(), "")
+		}
+		return pages.ImportResult("読み込みました", sys.Summary(sys.DefaultDocument()).URL)
+	}))
+
+	return r
+}
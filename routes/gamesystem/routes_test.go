@@ -0,0 +1,280 @@
+package gamesystem
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"charaxiv/templates/shared"
+)
+
+// fakeSystem is an in-memory game system
+type fakeSystem struct {
+	id, name string
+	docs     map[string]map[string]any
+}
+
+func newFakeSystem(id, name, memo string) *fakeSystem {
+	return &fakeSystem{id: id, name: name, docs: map[string]map[string]any{
+		"demo": {"memos": map[string]any{"public-memo": memo}},
+	}}
+}
+
+func (f *fakeSystem) ID() string              { return f.id }
+func (f *fakeSystem) Name() string            { return f.name }
+func (f *fakeSystem) DefaultDocument() string { return "demo" }
+func (f *fakeSystem) Router() chi.Router      { return chi.NewRouter() }
+
+func (f *fakeSystem) Summary(charID string) shared.CharacterSummary {
+	memo, _ := f.docs[charID]["memos"].(map[string]any)["public-memo"].(string)
+	return shared.CharacterSummary{
+		System:     f.id,
+		SystemName: f.name,
+		ID:         charID,
+		URL:        "/" + f.id + "/",
+		Stats:      []shared.SummaryStat{{Label: "HP", Value: "12"}},
+		Memo:       memo,
+	}
+}
+
+func (f *fakeSystem) Export(charID string) (map[string]any, error) {
+	return f.docs[charID], nil
+}
+
+func (f *fakeSystem) Import(charID string, data map[string]any) error {
+	if _, err := ReplaceOps([]Field{FieldOf[map[string]string]("memos")}, data); err != nil {
+		return err
+	}
+	f.docs[charID] = data
+	return nil
+}
+
+// RouteTest defines a test case for a route
+type RouteTest struct {
+	Method       string
+	Route        string
+	Desc         string
+	TestURL      string
+	Body         func() (*bytes.Buffer, string) // request body and content type
+	WantCode     int
+	WantContains []string
+}
+
+// multipartFile builds a multipart body uploading content as "file"
+func multipartFile(content string) func() (*bytes.Buffer, string) {
+	return func() (*bytes.Buffer, string) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		fw, _ := mw.CreateFormFile("file", "character.json")
+		fw.Write([]byte(content))
+		mw.Close()
+		return &buf, mw.FormDataContentType()
+	}
+}
+
+var routeTests = []RouteTest{
+	{
+		Method:       "GET",
+		Route:        "/",
+		Desc:         "Character index",
+		TestURL:      "/",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"character-list", "Alpha", "Beta", "/alpha/", "/export/beta"},
+	},
+	{
+		Method:       "GET",
+		Route:        "/search",
+		Desc:         "Search characters",
+		TestURL:      "/search?q=" + "detective",
+		WantCode:     http.StatusOK,
+		WantContains: []string{"character-list", "Alpha"},
+	},
+	{
+		Method:       "GET",
+		Route:        "/export/{system}",
+		Desc:         "Export default document",
+		TestURL:      "/export/alpha",
+		WantCode:     http.StatusOK,
+		WantContains: []string{`"system":"alpha"`, "a detective", "application/json"},
+	},
+	{
+		Method:   "GET",
+		Route:    "/export/{system}",
+		Desc:     "Export unknown system",
+		TestURL:  "/export/gamma",
+		WantCode: http.StatusNotFound,
+	},
+	{
+		Method:       "POST",
+		Route:        "/import",
+		Desc:         "Import document",
+		TestURL:      "/import",
+		Body:         multipartFile(`{"system":"beta","data":{"memos":{"public-memo":"imported"}}}`),
+		WantCode:     http.StatusOK,
+		WantContains: []string{"import-result", "読み込みました", "/beta/"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/import",
+		Desc:         "Import unknown system",
+		TestURL:      "/import",
+		Body:         multipartFile(`{"system":"gamma","data":{}}`),
+		WantCode:     http.StatusOK,
+		WantContains: []string{"import-result", "未対応のシステム"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/import",
+		Desc:         "Import unknown field",
+		TestURL:      "/import",
+		Body:         multipartFile(`{"system":"beta","data":{"weapons":[]}}`),
+		WantCode:     http.StatusOK,
+		WantContains: []string{"import-result", "unknown field"},
+	},
+	{
+		Method:       "POST",
+		Route:        "/import",
+		Desc:         "Import malformed value",
+		TestURL:      "/import",
+		Body:         multipartFile(`{"system":"beta","data":{"memos":"x"}}`),
+		WantCode:     http.StatusOK,
+		WantContains: []string{"import-result", "cannot unmarshal"},
+	},
+}
+
+func setupTestRouter() (chi.Router, *Registry) {
+	reg := NewRegistry(
+		newFakeSystem("alpha", "Alpha", "a detective"),
+		newFakeSystem("beta", "Beta", "a doctor"),
+	)
+	return Routes(reg), reg
+}
+
+// TestRoutes runs all route tests from the table
+func TestRoutes(t *testing.T) {
+	for _, tt := range routeTests {
+		t.Run(tt.Desc, func(t *testing.T) {
+			r, _ := setupTestRouter()
+
+			req := httptest.NewRequest(tt.Method, tt.TestURL, nil)
+			if tt.Body != nil {
+				body, contentType := tt.Body()
+				req = httptest.NewRequest(tt.Method, tt.TestURL, body)
+				req.Header.Set("Content-Type", contentType)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.WantCode {
+				t.Errorf("Expected status %d, got %d", tt.WantCode, w.Code)
+			}
+			fullResponse := w.Body.String() + w.Header().Get("Content-Type")
+			for _, want := range tt.WantContains {
+				if !strings.Contains(fullResponse, want) {
+					t.Errorf("Expected response to contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+// TestAllRoutesHaveTests verifies every registered route has a test case
+func TestAllRoutesHaveTests(t *testing.T) {
+	r, _ := setupTestRouter()
+
+	testedRoutes := make(map[string]bool)
+	for _, tt := range routeTests {
+		testedRoutes[tt.Method+" "+tt.Route] = true
+	}
+	chi.Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if !testedRoutes[method+" "+route] {
+			t.Errorf("Route has no test: %s %s", method, route)
+		}
+		return nil
+	})
+}
+
+// TestSearchMatchesAllTerms tests that every term must match, case-insensitively
+func TestSearchMatchesAllTerms(t *testing.T) {
+	_, reg := setupTestRouter()
+	summaries := reg.Summaries()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"alpha", "beta"}},
+		{"DOCTOR", []string{"beta"}},
+		{"hp 12", []string{"alpha", "beta"}},
+		{"alpha doctor", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, s := range Search(summaries, tt.query) {
+			got = append(got, s.System)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+// TestImportReplacesDocument tests that an exported document imports back unchanged
+func TestImportReplacesDocument(t *testing.T) {
+	r, reg := setupTestRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/export/alpha", nil))
+	var doc Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Export is not a document: %v", err)
+	}
+
+	doc.System = "beta"
+	b, _ := json.Marshal(doc)
+	body, contentType := multipartFile(string(b))()
+	req := httptest.NewRequest("POST", "/import", body)
+	req.Header.Set("Content-Type", contentType)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	beta, _ := reg.Lookup("beta")
+	if got := beta.Summary("demo").Memo; got != "a detective" {
+		t.Errorf("Expected imported memo, got %q", got)
+	}
+}
+
+// TestImportRejectsMalformedValue tests that a value that does not decode
+// into its field's type is rejected before anything is replaced
+func TestImportRejectsMalformedValue(t *testing.T) {
+	r, reg := setupTestRouter()
+
+	body, contentType := multipartFile(`{"system":"beta","data":{"memos":{"public-memo":1}}}`)()
+	req := httptest.NewRequest("POST", "/import", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), "読み込めません") {
+		t.Errorf("Expected the import to be rejected, got %s", w.Body.String())
+	}
+	beta, _ := reg.Lookup("beta")
+	if got := beta.Summary("demo").Memo; got != "a doctor" {
+		t.Errorf("Expected the document unchanged, got memo %q", got)
+	}
+}
+
+// TestNewRegistryRejectsDuplicateIDs tests that system IDs must be unique
+func TestNewRegistryRejectsDuplicateIDs(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for duplicate system IDs")
+		}
+	}()
+	NewRegistry(newFakeSystem("alpha", "A", ""), newFakeSystem("alpha", "B", ""))
+}
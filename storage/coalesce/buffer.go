@@ -0,0 +1,49 @@
+package coalesce
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Buffer defines the interface for the pending write log.
+// Implementations store JSON-encoded writes and return them in insertion order.
+type Buffer interface {
+	// Append stores a batch of entries atomically.
+	// batchID is empty for single writes.
+	Append(ctx context.Context, characterID, batchID string, entries []Entry) error
+
+	// Pending returns buffered writes for a character in insertion order.
+	Pending(ctx context.Context, characterID string) ([]PendingWrite, error)
+
+	// Clear removes the given writes (by Seq) after they have been flushed.
+	// Writes buffered after the Pending snapshot must survive.
+	Clear(ctx context.Context, characterID string, seqs []int64) error
+
+	// Characters returns the IDs of characters with pending writes.
+	Characters(ctx context.Context) ([]string, error)
+
+	// Lock holds a character until unlock is called, so processes sharing
+	// the buffer never interleave a flush's load, save and clear. Buffers
+	// used by a single process rely on the Store's own locks and return a
+	// no-op unlock.
+	Lock(ctx context.Context, characterID string) (unlock func(), err error)
+
+	// Close releases resources held by the buffer.
+	Close() error
+}
+
+// Entry is a single encoded write passed to a Buffer.
+type Entry struct {
+	Path  string
+	Value json.RawMessage
+}
+
+// PendingWrite is a buffered write as returned by a Buffer.
+type PendingWrite struct {
+	Seq       int64 // monotonically increasing insertion sequence
+	BatchID   string
+	Path      string
+	Value     json.RawMessage
+	CreatedAt time.Time // when the write was buffered
+}
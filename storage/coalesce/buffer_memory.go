@@ -0,0 +1,100 @@
+package coalesce
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryBuffer keeps pending writes in process memory.
+// Useful for tests; pending writes are lost when the process exits.
+type MemoryBuffer struct {
+	mu      sync.Mutex
+	seq     int64
+	pending map[string][]PendingWrite
+}
+
+// NewMemoryBuffer creates an empty in-memory buffer.
+func NewMemoryBuffer() *MemoryBuffer {
+	return &MemoryBuffer{
+		pending: make(map[string][]PendingWrite),
+	}
+}
+
+// Append stores all entries under a single lock.
+func (b *MemoryBuffer) Append(_ context.Context, characterID, batchID string, entries []Entry) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	createdAt := time.Now()
+	for _, e := range entries {
+		b.seq++
+		b.pending[characterID] = append(b.pending[characterID], PendingWrite{
+			Seq:       b.seq,
+			BatchID:   batchID,
+			Path:      e.Path,
+			Value:     bytes.Clone(e.Value),
+			CreatedAt: createdAt,
+		})
+	}
+	return nil
+}
+
+// Pending returns a copy of the buffered writes in insertion order.
+func (b *MemoryBuffer) Pending(_ context.Context, characterID string) ([]PendingWrite, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list := b.pending[characterID]
+	if len(list) == 0 {
+		return nil, nil
+	}
+	out := make([]PendingWrite, len(list))
+	copy(out, list)
+	return out, nil
+}
+
+// Clear removes the given writes.
+func (b *MemoryBuffer) Clear(_ context.Context, characterID string, seqs []int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var kept []PendingWrite
+	for _, p := range b.pending[characterID] {
+		if !slices.Contains(seqs, p.Seq) {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == 0 {
+		delete(b.pending, characterID)
+		return nil
+	}
+	b.pending[characterID] = kept
+	return nil
+}
+
+// Characters returns the IDs of characters with pending writes.
+func (b *MemoryBuffer) Characters(_ context.Context) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ids := make([]string, 0, len(b.pending))
+	for id := range b.pending {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Lock is a no-op; the buffer is private to one process.
+func (b *MemoryBuffer) Lock(context.Context, string) (func(), error) {
+	return func() {}, nil
+}
+
+// Close is a no-op.
+func (b *MemoryBuffer) Close() error {
+	return nil
+}
+
+var _ Buffer = (*MemoryBuffer)(nil)
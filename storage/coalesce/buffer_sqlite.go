@@ -0,0 +1,207 @@
+package coalesce
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteBuffer stores pending writes in a local SQLite database.
+type SQLiteBuffer struct {
+	db *sql.DB
+}
+
+// NewSQLiteBuffer opens (or creates) a SQLite write buffer at dbPath.
+func NewSQLiteBuffer(dbPath string) (*SQLiteBuffer, error) {
+	// Ensure directory exists for SQLite buffer
+	if dir := filepath.Dir(dbPath); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("create buffer dir: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", dbPath+"?_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS write_buffer (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			character_id TEXT NOT NULL,
+			path TEXT NOT NULL,
+			value TEXT NOT NULL,
+			batch_id TEXT,
+			created_at INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS idx_buffer_char ON write_buffer(character_id);
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	// Buffers created by older versions lack these columns
+	if err := ensureColumn(db, "write_buffer", "batch_id", "TEXT"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := ensureColumn(db, "write_buffer", "created_at", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteBuffer{db: db}, nil
+}
+
+// Append inserts all entries in one transaction.
+func (b *SQLiteBuffer) Append(ctx context.Context, characterID, batchID string, entries []Entry) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin batch: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO write_buffer (character_id, path, value, batch_id, created_at) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare batch: %w", err)
+	}
+	defer stmt.Close()
+
+	var batch sql.NullString
+	if batchID != "" {
+		batch = sql.NullString{String: batchID, Valid: true}
+	}
+	createdAt := time.Now().UnixMilli()
+	for _, e := range entries {
+		if _, err := stmt.ExecContext(ctx, characterID, e.Path, string(e.Value), batch, createdAt); err != nil {
+			return fmt.Errorf("insert buffer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit batch: %w", err)
+	}
+	return nil
+}
+
+// Pending returns buffered writes in insertion order.
+// The single query is a consistent snapshot, so batches committed in one
+// transaction are either fully included or fully absent.
+func (b *SQLiteBuffer) Pending(ctx context.Context, characterID string) ([]PendingWrite, error) {
+	rows, err := b.db.QueryContext(ctx,
+		`SELECT id, batch_id, path, value, created_at FROM write_buffer WHERE character_id = ? ORDER BY id`,
+		characterID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query buffer: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []PendingWrite
+	for rows.Next() {
+		var p PendingWrite
+		var batch sql.NullString
+		var value string
+		var createdAt int64
+		if err := rows.Scan(&p.Seq, &batch, &p.Path, &value, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan buffer: %w", err)
+		}
+		p.BatchID = batch.String
+		p.Value = []byte(value)
+		p.CreatedAt = time.UnixMilli(createdAt)
+		pending = append(pending, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate buffer: %w", err)
+	}
+
+	return pending, nil
+}
+
+// Clear removes flushed writes.
+// SQLite serializes writers, so ids commit in order and deleting up to the
+// highest flushed id never drops a write outside the snapshot.
+func (b *SQLiteBuffer) Clear(ctx context.Context, characterID string, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	maxSeq := slices.Max(seqs)
+	_, err := b.db.ExecContext(ctx, `DELETE FROM write_buffer WHERE character_id = ? AND id <= ?`, characterID, maxSeq)
+	if err != nil {
+		return fmt.Errorf("clear buffer: %w", err)
+	}
+	return nil
+}
+
+// Characters returns the IDs of characters with pending writes.
+func (b *SQLiteBuffer) Characters(ctx context.Context) ([]string, error) {
+	return queryCharacters(ctx, b.db)
+}
+
+// Lock is a no-op; the database file belongs to one process.
+func (b *SQLiteBuffer) Lock(context.Context, string) (func(), error) {
+	return func() {}, nil
+}
+
+// Close closes the database.
+func (b *SQLiteBuffer) Close() error {
+	return b.db.Close()
+}
+
+// queryCharacters lists distinct character IDs in a write_buffer table
+func queryCharacters(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT DISTINCT character_id FROM write_buffer`)
+	if err != nil {
+		return nil, fmt.Errorf("query buffered characters: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan buffered characters: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate buffered characters: %w", err)
+	}
+	return ids, nil
+}
+
+// ensureColumn adds a column to a table if it does not exist yet
+func ensureColumn(db *sql.DB, table, column, decl string) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return fmt.Errorf("inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("scan %s columns: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate %s columns: %w", table, err)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl)); err != nil {
+		return fmt.Errorf("add %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+var _ Buffer = (*SQLiteBuffer)(nil)
@@ -1,33 +1,44 @@
 // Package coalesce implements write coalescing storage.
-// Writes are buffered in SQLite and flushed to a backend on read.
+// Writes are buffered in a pluggable Buffer (SQLite by default) and flushed to
+// a backend on Read, or according to a FlushPolicy when using View.
 package coalesce
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
-	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
-	_ "modernc.org/sqlite"
+	"github.com/google/uuid"
 )
 
-// Store handles write coalescing with SQLite buffer and pluggable backend persistence.
+// lockStripes is the number of per-character lock stripes.
+// Reads of characters on different stripes proceed in parallel.
+const lockStripes = 64
+
+// Store handles write coalescing with a pending-write buffer and pluggable backend persistence.
 type Store struct {
-	db      *sql.DB
+	buffer  Buffer
 	backend Backend
-	mu      sync.Mutex
+	policy  FlushPolicy
+	locks   [lockStripes]sync.Mutex
+	now     func() time.Time
+
+	subMu   sync.RWMutex
+	subs    map[int]chan Event
+	nextSub int
 }
 
 // Config for creating a new Store
 type Config struct {
-	DBPath  string  // SQLite database path for write buffer
-	Backend Backend // Backend for persistent storage
+	DBPath  string      // SQLite database path for write buffer (used when Buffer is nil)
+	Buffer  Buffer      // Optional write buffer; defaults to a SQLiteBuffer at DBPath
+	Backend Backend     // Backend for persistent storage
+	Flush   FlushPolicy // When View and FlushDue persist pending writes
 }
 
 // New creates a new coalescing store
@@ -36,65 +47,93 @@ func New(cfg Config) (*Store, error) {
 		return nil, fmt.Errorf("backend is required")
 	}
 
-	// Ensure directory exists for SQLite buffer
-	if dir := filepath.Dir(cfg.DBPath); dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return nil, fmt.Errorf("create buffer dir: %w", err)
+	buffer := cfg.Buffer
+	if buffer == nil {
+		sqliteBuffer, err := NewSQLiteBuffer(cfg.DBPath)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	// Open SQLite
-	db, err := sql.Open("sqlite", cfg.DBPath+"?_journal_mode=WAL")
-	if err != nil {
-		return nil, fmt.Errorf("open sqlite: %w", err)
-	}
-
-	// Create buffer table
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS write_buffer (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			character_id TEXT NOT NULL,
-			path TEXT NOT NULL,
-			value TEXT NOT NULL
-		);
-		CREATE INDEX IF NOT EXISTS idx_buffer_char ON write_buffer(character_id);
-	`)
-	if err != nil {
-		db.Close()
-		return nil, fmt.Errorf("create table: %w", err)
+		buffer = sqliteBuffer
 	}
 
 	return &Store{
-		db:      db,
+		buffer:  buffer,
 		backend: cfg.Backend,
+		policy:  cfg.Flush,
+		now:     time.Now,
 	}, nil
 }
 
-// Close closes the database
+// Close closes subscriber channels and the write buffer
 func (s *Store) Close() error {
-	return s.db.Close()
+	s.closeSubscribers()
+	return s.buffer.Close()
 }
 
 // Write buffers a write operation
 func (s *Store) Write(characterID, path string, value any) error {
 	start := time.Now()
-	valueJSON, err := json.Marshal(value)
+	entries, err := encodeOps([]Op{{Path: path, Value: value}})
 	if err != nil {
-		return fmt.Errorf("marshal value: %w", err)
+		return err
 	}
 
-	_, err = s.db.Exec(
-		`INSERT INTO write_buffer (character_id, path, value) VALUES (?, ?, ?)`,
-		characterID, path, string(valueJSON),
-	)
-	if err != nil {
-		return fmt.Errorf("insert buffer: %w", err)
+	if err := s.buffer.Append(context.Background(), characterID, "", entries); err != nil {
+		return err
+	}
+	if s.hasSubscribers() {
+		s.publish(Event{Type: EventWrite, CharacterID: characterID, Changes: changesFromEntries(entries), Time: s.now()})
 	}
 
 	slog.Debug("coalesce.write", "characterID", characterID, "path", path, "duration", time.Since(start))
 	return nil
 }
 
+// Op is a single path write within a batch.
+type Op struct {
+	Path  string
+	Value any
+}
+
+// WriteBatch buffers several write operations atomically.
+// All operations are inserted in one transaction under a shared batch ID,
+// so a flush either sees the whole batch or none of it.
+func (s *Store) WriteBatch(characterID string, ops []Op) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	start := time.Now()
+	batchID := uuid.NewString()
+
+	entries, err := encodeOps(ops)
+	if err != nil {
+		return err
+	}
+
+	if err := s.buffer.Append(context.Background(), characterID, batchID, entries); err != nil {
+		return err
+	}
+	if s.hasSubscribers() {
+		s.publish(Event{Type: EventWrite, CharacterID: characterID, BatchID: batchID, Changes: changesFromEntries(entries), Time: s.now()})
+	}
+
+	slog.Debug("coalesce.writeBatch", "characterID", characterID, "batchID", batchID, "ops", len(ops), "duration", time.Since(start))
+	return nil
+}
+
+// encodeOps marshals op values to JSON for the buffer
+func encodeOps(ops []Op) ([]Entry, error) {
+	entries := make([]Entry, len(ops))
+	for i, op := range ops {
+		valueJSON, err := json.Marshal(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("marshal value for %s: %w", op.Path, err)
+		}
+		entries[i] = Entry{Path: op.Path, Value: valueJSON}
+	}
+	return entries, nil
+}
+
 // Read returns the current state for a character, flushing any pending writes
 func (s *Store) Read(characterID string) (map[string]any, error) {
 	return s.ReadWithContext(context.Background(), characterID)
@@ -103,93 +142,139 @@ func (s *Store) Read(characterID string) (map[string]any, error) {
 // ReadWithContext returns the current state for a character, flushing any pending writes
 func (s *Store) ReadWithContext(ctx context.Context, characterID string) (map[string]any, error) {
 	start := time.Now()
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	mu := s.lockFor(characterID)
+	mu.Lock()
+	defer mu.Unlock()
 
-	// Load from backend
-	loadStart := time.Now()
-	data, err := s.backend.Load(ctx, characterID)
+	data, pending, err := s.persistLatest(ctx, characterID)
 	if err != nil {
 		return nil, err
 	}
-	if data == nil {
-		data = make(map[string]any)
-	}
-	loadDuration := time.Since(loadStart)
 
-	// Get pending writes
-	pending, err := s.getPendingWrites(characterID)
+	slog.Debug("coalesce.read",
+		"characterID", characterID,
+		"pendingWrites", len(pending),
+		"duration", time.Since(start),
+	)
+	return data, nil
+}
+
+// View returns the backend state merged with pending writes without persisting them.
+// Persistence is left to the flush policy: if the pending writes are due
+// (see FlushPolicy) they are flushed as part of the view.
+func (s *Store) View(ctx context.Context, characterID string) (map[string]any, error) {
+	start := time.Now()
+	mu := s.lockFor(characterID)
+	mu.Lock()
+	defer mu.Unlock()
+
+	data, pending, err := s.merged(ctx, characterID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Apply and flush if there are pending writes
-	var flushDuration time.Duration
-	if len(pending) > 0 {
-		flushStart := time.Now()
-		for _, p := range pending {
-			setPath(data, p.path, p.value)
-		}
-
-		if err := s.backend.Save(ctx, characterID, data); err != nil {
-			return nil, err
-		}
-
-		if err := s.clearBuffer(characterID); err != nil {
+	flushed := false
+	if s.policy.due(pending, s.now()) {
+		if data, pending, err = s.persistLatest(ctx, characterID); err != nil {
 			return nil, err
 		}
-		flushDuration = time.Since(flushStart)
+		flushed = true
 	}
 
-	slog.Debug("coalesce.read",
+	slog.Debug("coalesce.view",
 		"characterID", characterID,
 		"pendingWrites", len(pending),
-		"loadDuration", loadDuration,
-		"flushDuration", flushDuration,
+		"flushed", flushed,
 		"duration", time.Since(start),
 	)
 	return data, nil
 }
 
-type pendingWrite struct {
-	path  string
-	value any
-}
+// merged loads backend state and applies pending writes in memory.
+// Callers must hold the character's lock.
+func (s *Store) merged(ctx context.Context, characterID string) (map[string]any, []PendingWrite, error) {
+	data, err := s.backend.Load(ctx, characterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data == nil {
+		data = make(map[string]any)
+	}
 
-func (s *Store) getPendingWrites(characterID string) ([]pendingWrite, error) {
-	rows, err := s.db.Query(
-		`SELECT path, value FROM write_buffer WHERE character_id = ? ORDER BY id`,
-		characterID,
-	)
+	pending, err := s.buffer.Pending(ctx, characterID)
 	if err != nil {
-		return nil, fmt.Errorf("query buffer: %w", err)
+		return nil, nil, err
 	}
-	defer rows.Close()
 
-	var pending []pendingWrite
-	for rows.Next() {
-		var path, valueJSON string
-		if err := rows.Scan(&path, &valueJSON); err != nil {
-			return nil, fmt.Errorf("scan buffer: %w", err)
-		}
+	for _, p := range pending {
 		var value any
-		if err := json.Unmarshal([]byte(valueJSON), &value); err != nil {
-			return nil, fmt.Errorf("unmarshal value: %w", err)
+		if err := json.Unmarshal(p.Value, &value); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal value: %w", err)
 		}
-		pending = append(pending, pendingWrite{path: path, value: value})
+		setPath(data, p.Path, value)
 	}
-
-	return pending, nil
+	return data, pending, nil
 }
 
-func (s *Store) clearBuffer(characterID string) error {
-	_, err := s.db.Exec(`DELETE FROM write_buffer WHERE character_id = ?`, characterID)
+// persistLatest merges and persists a character's pending writes under the
+// buffer's lock. The merge is redone under that lock, so a snapshot taken
+// before another process flushed is never saved over its newer state.
+// Callers must hold the character's lock.
+func (s *Store) persistLatest(ctx context.Context, characterID string) (map[string]any, []PendingWrite, error) {
+	unlock, err := s.buffer.Lock(ctx, characterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	data, pending, err := s.merged(ctx, characterID)
 	if err != nil {
-		return fmt.Errorf("clear buffer: %w", err)
+		return nil, nil, err
+	}
+	if len(pending) > 0 {
+		if err := s.persist(ctx, characterID, data, pending); err != nil {
+			return nil, nil, err
+		}
+	}
+	return data, pending, nil
+}
+
+// persist saves merged data and clears the writes it contains.
+// Callers must hold the character's lock and the buffer's lock.
+func (s *Store) persist(ctx context.Context, characterID string, data map[string]any, pending []PendingWrite) error {
+	if err := s.backend.Save(ctx, characterID, data); err != nil {
+		return err
+	}
+
+	// Only clear what was applied; writes buffered after the snapshot stay pending
+	seqs := make([]int64, len(pending))
+	for i, p := range pending {
+		seqs[i] = p.Seq
+	}
+	if err := s.buffer.Clear(ctx, characterID, seqs); err != nil {
+		return err
+	}
+
+	if s.hasSubscribers() {
+		entries := make([]Entry, len(pending))
+		for i, p := range pending {
+			entries[i] = Entry{Path: p.Path, Value: p.Value}
+		}
+		s.publish(Event{Type: EventFlush, CharacterID: characterID, Changes: changesFromEntries(entries), Time: s.now()})
 	}
 	return nil
 }
 
+// lockFor returns the lock stripe guarding a character.
+// Load-apply-save must not interleave for the same character, but a slow
+// backend call for one character shouldn't block the others. The stripes
+// only cover this process; Buffer.Lock covers processes sharing a buffer.
+func (s *Store) lockFor(characterID string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(characterID))
+	return &s.locks[h.Sum32()%lockStripes]
+}
+
 // setPath sets a value at a dot-separated path in a nested map
 // e.g., setPath(data, "skills.回避.job", 5)
 func setPath(data map[string]any, path string, value any) {
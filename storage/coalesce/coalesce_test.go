@@ -1,9 +1,16 @@
 package coalesce
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestStore(t *testing.T) {
@@ -154,3 +161,489 @@ func TestSetPath(t *testing.T) {
 		t.Errorf("expected a.b.d=hello, got %v", b["d"])
 	}
 }
+
+// newTestStore creates a store with a disk backend in a temp directory.
+func newTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	backend, err := NewDiskBackend(filepath.Join(tmpDir, "data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := New(Config{
+		DBPath:  filepath.Join(tmpDir, "buffer.db"),
+		Backend: backend,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store, tmpDir
+}
+
+func TestWriteBatch(t *testing.T) {
+	store, _ := newTestStore(t)
+	charID := "char-batch"
+
+	err := store.WriteBatch(charID, []Op{
+		{Path: "status.variables.STR.base", Value: 12},
+		{Path: "status.variables.CON.base", Value: 9},
+		{Path: "skills.extra", Value: map[string]int{"job": 10, "hobby": 0}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// All ops share one batch ID
+	pending, err := store.buffer.Pending(context.Background(), charID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 3 {
+		t.Fatalf("expected 3 pending writes, got %d", len(pending))
+	}
+	for _, p := range pending {
+		if p.BatchID == "" || p.BatchID != pending[0].BatchID {
+			t.Errorf("expected shared batch ID, got %q and %q", p.BatchID, pending[0].BatchID)
+		}
+	}
+
+	data, err := store.Read(charID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := data["status"].(map[string]any)["variables"].(map[string]any)
+	if vars["STR"].(map[string]any)["base"] != float64(12) {
+		t.Errorf("expected STR=12, got %v", vars["STR"])
+	}
+	if vars["CON"].(map[string]any)["base"] != float64(9) {
+		t.Errorf("expected CON=9, got %v", vars["CON"])
+	}
+}
+
+func TestWriteBatchIsAtomic(t *testing.T) {
+	store, _ := newTestStore(t)
+	charID := "char-atomic"
+
+	// A value that can't be marshaled aborts the whole batch
+	err := store.WriteBatch(charID, []Op{
+		{Path: "a", Value: 1},
+		{Path: "b", Value: make(chan int)},
+	})
+	if err == nil {
+		t.Fatal("expected marshal error")
+	}
+
+	pending, err := store.buffer.Pending(context.Background(), charID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no buffered writes after failed batch, got %d", len(pending))
+	}
+}
+
+func TestMigratesBufferWithoutBatchColumn(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "buffer.db")
+
+	// Create a buffer with the pre-batch schema
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE write_buffer (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			character_id TEXT NOT NULL,
+			path TEXT NOT NULL,
+			value TEXT NOT NULL
+		);
+		INSERT INTO write_buffer (character_id, path, value) VALUES ('old', 'name', '"legacy"');
+	`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	backend, err := NewDiskBackend(filepath.Join(tmpDir, "data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := New(Config{DBPath: dbPath, Backend: backend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.WriteBatch("old", []Op{{Path: "ruby", Value: "れがしー"}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.Read("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["name"] != "legacy" || data["ruby"] != "れがしー" {
+		t.Errorf("unexpected data after migration: %v", data)
+	}
+}
+
+// memoryBackend is an in-memory Backend with an optional artificial latency.
+type memoryBackend struct {
+	mu      sync.Mutex
+	data    map[string]map[string]any
+	latency time.Duration
+	loading chan string // if set, receives the character ID on every Load
+	release chan struct{}
+}
+
+func newMemoryBackend(latency time.Duration) *memoryBackend {
+	return &memoryBackend{data: make(map[string]map[string]any), latency: latency}
+}
+
+func (b *memoryBackend) Load(_ context.Context, characterID string) (map[string]any, error) {
+	if b.loading != nil {
+		b.loading <- characterID
+		<-b.release
+	}
+	time.Sleep(b.latency)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stored, ok := b.data[characterID]
+	if !ok {
+		return nil, nil
+	}
+	// Round-trip through JSON so callers can't mutate stored state
+	raw, _ := json.Marshal(stored)
+	var out map[string]any
+	json.Unmarshal(raw, &out)
+	return out, nil
+}
+
+func (b *memoryBackend) Save(_ context.Context, characterID string, data map[string]any) error {
+	time.Sleep(b.latency)
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var stored map[string]any
+	json.Unmarshal(raw, &stored)
+	b.mu.Lock()
+	b.data[characterID] = stored
+	b.mu.Unlock()
+	return nil
+}
+
+func TestReadsOfDifferentCharactersRunConcurrently(t *testing.T) {
+	backend := newMemoryBackend(0)
+	backend.loading = make(chan string)
+	backend.release = make(chan struct{})
+
+	store, err := New(Config{Buffer: NewMemoryBuffer(), Backend: backend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// Pick two characters on different stripes
+	a, b := "char-a", "char-b"
+	for i := 0; store.lockFor(a) == store.lockFor(b); i++ {
+		b = fmt.Sprintf("char-b-%d", i)
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range []string{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Read(id)
+		}()
+	}
+
+	// Both loads must be in flight at once; with a global lock the second never starts
+	seen := map[string]bool{}
+	timeout := time.After(2 * time.Second)
+	for len(seen) < 2 {
+		select {
+		case id := <-backend.loading:
+			seen[id] = true
+		case <-timeout:
+			t.Fatalf("expected concurrent loads, only saw %v", seen)
+		}
+	}
+	close(backend.release)
+	wg.Wait()
+}
+
+// BenchmarkReadParallel measures read throughput against a backend with
+// 1ms latency per call, with and without pending writes to flush.
+func BenchmarkReadParallel(b *testing.B) {
+	for _, bc := range []struct {
+		name       string
+		characters int
+		write      bool // write before each read, leaving a pending write to flush
+	}{
+		{"SameCharacter", 1, true},
+		{"ManyCharacters", 256, true},
+		{"ReadOnly", 256, false},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			store, err := New(Config{Buffer: NewMemoryBuffer(), Backend: newMemoryBackend(time.Millisecond)})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer store.Close()
+
+			var next atomic.Int64
+			b.SetParallelism(16)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					id := fmt.Sprintf("char-%d", next.Add(1)%int64(bc.characters))
+					if bc.write {
+						store.Write(id, "skills.回避.job", 5)
+					}
+					if _, err := store.Read(id); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestViewDoesNotPersist(t *testing.T) {
+	backend := newMemoryBackend(0)
+	store, err := New(Config{Buffer: NewMemoryBuffer(), Backend: backend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	store.Write("view", "name", "探索者")
+	data, err := store.View(context.Background(), "view")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["name"] != "探索者" {
+		t.Errorf("expected merged name, got %v", data["name"])
+	}
+	if _, ok := backend.data["view"]; ok {
+		t.Error("expected View not to save to backend")
+	}
+
+	// Explicit flush persists
+	if err := store.Flush(context.Background(), "view"); err != nil {
+		t.Fatal(err)
+	}
+	if backend.data["view"]["name"] != "探索者" {
+		t.Errorf("expected flushed name, got %v", backend.data["view"])
+	}
+}
+
+func TestViewFlushesWhenPolicyDue(t *testing.T) {
+	backend := newMemoryBackend(0)
+	store, err := New(Config{
+		Buffer:  NewMemoryBuffer(),
+		Backend: backend,
+		Flush:   FlushPolicy{MaxPending: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	store.Write("size", "a", 1)
+	store.Write("size", "b", 2)
+	store.View(ctx, "size")
+	if _, ok := backend.data["size"]; ok {
+		t.Fatal("expected no flush below MaxPending")
+	}
+
+	store.Write("size", "c", 3)
+	store.View(ctx, "size")
+	if backend.data["size"]["c"] != float64(3) {
+		t.Errorf("expected flush at MaxPending, got %v", backend.data["size"])
+	}
+}
+
+// lockHookBuffer runs onLock the first time a flush takes the buffer's lock,
+// standing in for another replica that flushes while this one waits
+type lockHookBuffer struct {
+	Buffer
+	onLock func()
+}
+
+func (b *lockHookBuffer) Lock(ctx context.Context, characterID string) (func(), error) {
+	if f := b.onLock; f != nil {
+		b.onLock = nil
+		f()
+	}
+	return b.Buffer.Lock(ctx, characterID)
+}
+
+func TestFlushDoesNotSaveStaleSnapshot(t *testing.T) {
+	shared := NewMemoryBuffer()
+	backend := newMemoryBackend(0)
+	hooked := &lockHookBuffer{Buffer: shared}
+	a, err := New(Config{Buffer: hooked, Backend: backend, Flush: FlushPolicy{MaxPending: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := New(Config{Buffer: shared, Backend: backend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	a.Write("shared", "name", "old")
+	// Replica B writes and flushes after A's snapshot but before A's save
+	hooked.onLock = func() {
+		b.Write("shared", "name", "new")
+		if _, err := b.Read("shared"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := a.View(context.Background(), "shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["name"] != "new" {
+		t.Errorf("expected view of B's flush, got %v", data["name"])
+	}
+	if got := backend.data["shared"]["name"]; got != "new" {
+		t.Errorf("expected B's write to survive, got %v", got)
+	}
+}
+
+func TestFlushDueByAge(t *testing.T) {
+	backend := newMemoryBackend(0)
+	store, err := New(Config{
+		Buffer:  NewMemoryBuffer(),
+		Backend: backend,
+		Flush:   FlushPolicy{MaxAge: time.Minute},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	store.Write("aged", "a", 1)
+
+	if err := store.FlushDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.data) != 0 {
+		t.Fatalf("expected nothing flushed yet, got %v", backend.data)
+	}
+
+	// Advance the clock past MaxAge
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if err := store.FlushDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := backend.data["aged"]; !ok {
+		t.Error("expected aged writes to be flushed")
+	}
+}
+
+func TestFlushAll(t *testing.T) {
+	backend := newMemoryBackend(0)
+	buffer := NewMemoryBuffer()
+	store, err := New(Config{Buffer: buffer, Backend: backend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	store.Write("one", "a", 1)
+	store.Write("two", "a", 2)
+	if err := store.FlushAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backend.data) != 2 {
+		t.Errorf("expected 2 characters saved, got %d", len(backend.data))
+	}
+	ids, _ := buffer.Characters(ctx)
+	if len(ids) != 0 {
+		t.Errorf("expected empty buffer after FlushAll, got %v", ids)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	store, err := New(Config{Buffer: NewMemoryBuffer(), Backend: newMemoryBackend(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	events, cancel := store.Subscribe(10)
+	defer cancel()
+
+	store.Write("ev", "name", "探索者")
+	store.WriteBatch("ev", []Op{
+		{Path: "status.variables.STR.base", Value: 12},
+		{Path: "status.variables.CON.base", Value: 9},
+	})
+	store.Flush(context.Background(), "ev")
+
+	e := <-events
+	if e.Type != EventWrite || e.CharacterID != "ev" || e.Changes[0].Value != "探索者" {
+		t.Errorf("unexpected write event: %+v", e)
+	}
+
+	e = <-events
+	if e.Type != EventWrite || e.BatchID == "" || len(e.Changes) != 2 {
+		t.Errorf("unexpected batch event: %+v", e)
+	}
+	if e.Changes[0].Value != float64(12) {
+		t.Errorf("expected STR value 12, got %v", e.Changes[0].Value)
+	}
+
+	e = <-events
+	if e.Type != EventFlush {
+		t.Fatalf("expected flush event, got %+v", e)
+	}
+	if got := e.Paths(); len(got) != 3 || got[0] != "name" {
+		t.Errorf("unexpected flushed paths: %v", got)
+	}
+}
+
+func TestSubscribeDropsWhenFull(t *testing.T) {
+	store, err := New(Config{Buffer: NewMemoryBuffer(), Backend: newMemoryBackend(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	events, cancel := store.Subscribe(1)
+
+	// A subscriber that never reads must not block writers
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			store.Write("full", "a", i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("writes blocked on a full subscriber")
+	}
+
+	cancel()
+	count := 0
+	for range events {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("expected 1 buffered event, got %d", count)
+	}
+}
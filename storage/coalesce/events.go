@@ -0,0 +1,111 @@
+package coalesce
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// EventType identifies what happened to a character.
+type EventType string
+
+const (
+	// EventWrite is emitted after a Write or WriteBatch is buffered.
+	EventWrite EventType = "write"
+	// EventFlush is emitted after pending writes are persisted to the backend.
+	EventFlush EventType = "flush"
+)
+
+// Change is a single path and its new value.
+type Change struct {
+	Path  string
+	Value any
+}
+
+// Event describes a change to a character.
+// Events are delivered to subscribers in this process only.
+type Event struct {
+	Type        EventType
+	CharacterID string
+	BatchID     string   // set for WriteBatch events
+	Changes     []Change // written paths and values (write) or the flushed paths (flush)
+	Time        time.Time
+}
+
+// Paths returns the changed paths in order.
+func (e Event) Paths() []string {
+	paths := make([]string, len(e.Changes))
+	for i, c := range e.Changes {
+		paths[i] = c.Path
+	}
+	return paths
+}
+
+// Subscribe registers a subscriber and returns its event channel and a cancel function.
+// size is the channel buffer; events for a subscriber whose buffer is full are
+// dropped rather than blocking writers. Cancel closes the channel.
+func (s *Store) Subscribe(size int) (<-chan Event, func()) {
+	ch := make(chan Event, size)
+
+	s.subMu.Lock()
+	if s.subs == nil {
+		s.subs = make(map[int]chan Event)
+	}
+	id := s.nextSub
+	s.nextSub++
+	s.subs[id] = ch
+	s.subMu.Unlock()
+
+	cancel := func() {
+		s.subMu.Lock()
+		defer s.subMu.Unlock()
+		if c, ok := s.subs[id]; ok {
+			delete(s.subs, id)
+			close(c)
+		}
+	}
+	return ch, cancel
+}
+
+// publish delivers an event to every subscriber without blocking.
+func (s *Store) publish(e Event) {
+	s.subMu.RLock()
+	defer s.subMu.RUnlock()
+
+	for id, ch := range s.subs {
+		select {
+		case ch <- e:
+		default:
+			slog.Warn("coalesce.event dropped", "subscriber", id, "type", e.Type, "characterID", e.CharacterID)
+		}
+	}
+}
+
+// closeSubscribers closes every subscriber channel.
+func (s *Store) closeSubscribers() {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for id, ch := range s.subs {
+		delete(s.subs, id)
+		close(ch)
+	}
+}
+
+// hasSubscribers reports whether building an event is worth the effort.
+func (s *Store) hasSubscribers() bool {
+	s.subMu.RLock()
+	defer s.subMu.RUnlock()
+	return len(s.subs) > 0
+}
+
+// changesFromEntries decodes encoded entries so subscribers get independent copies.
+func changesFromEntries(entries []Entry) []Change {
+	changes := make([]Change, len(entries))
+	for i, e := range entries {
+		var value any
+		json.Unmarshal(e.Value, &value)
+		changes[i] = Change{Path: e.Path, Value: value}
+	}
+	return changes
+}
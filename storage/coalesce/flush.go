@@ -0,0 +1,98 @@
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// FlushPolicy decides when pending writes are persisted to the backend.
+// The zero value never flushes on its own; pending writes are then only
+// persisted by Read, Flush or FlushAll.
+type FlushPolicy struct {
+	MaxAge     time.Duration // Flush once the oldest pending write is this old (0 = no limit)
+	MaxPending int           // Flush once this many writes are pending (0 = no limit)
+}
+
+// due reports whether pending writes should be flushed now.
+func (p FlushPolicy) due(pending []PendingWrite, now time.Time) bool {
+	if len(pending) == 0 {
+		return false
+	}
+	if p.MaxPending > 0 && len(pending) >= p.MaxPending {
+		return true
+	}
+	if p.MaxAge > 0 && now.Sub(pending[0].CreatedAt) >= p.MaxAge {
+		return true
+	}
+	return false
+}
+
+// Flush persists pending writes for a character regardless of policy.
+func (s *Store) Flush(ctx context.Context, characterID string) error {
+	return s.flush(ctx, characterID, true)
+}
+
+// FlushDue persists pending writes for every character whose writes are due under the policy.
+func (s *Store) FlushDue(ctx context.Context) error {
+	return s.flushEach(ctx, false)
+}
+
+// FlushAll persists pending writes for every character (e.g., on shutdown).
+func (s *Store) FlushAll(ctx context.Context) error {
+	return s.flushEach(ctx, true)
+}
+
+// Run calls FlushDue every interval until ctx is canceled.
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.FlushDue(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("coalesce.flushDue failed", "error", err)
+			}
+		}
+	}
+}
+
+func (s *Store) flushEach(ctx context.Context, force bool) error {
+	ids, err := s.buffer.Characters(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, id := range ids {
+		if err := s.flush(ctx, id, force); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Store) flush(ctx context.Context, characterID string, force bool) error {
+	start := time.Now()
+	mu := s.lockFor(characterID)
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Check the buffer first so idle ticks don't load from the backend
+	pending, err := s.buffer.Pending(ctx, characterID)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 || (!force && !s.policy.due(pending, s.now())) {
+		return nil
+	}
+
+	if _, pending, err = s.persistLatest(ctx, characterID); err != nil {
+		return err
+	}
+
+	slog.Debug("coalesce.flush", "characterID", characterID, "pendingWrites", len(pending), "duration", time.Since(start))
+	return nil
+}
@@ -0,0 +1,139 @@
+package cthulhu6
+
+import (
+	"fmt"
+	"time"
+
+	"charaxiv/dice"
+)
+
+// SuccessLevel is the outcome of a 1D100 check
+type SuccessLevel string
+
+const (
+	LevelCritical SuccessLevel = "critical" // 決定的成功
+	LevelSpecial  SuccessLevel = "special"  // スペシャル
+	LevelSuccess  SuccessLevel = "success"  // 成功
+	LevelFailure  SuccessLevel = "failure"  // 失敗
+	LevelFumble   SuccessLevel = "fumble"   // 致命的失敗
+)
+
+// Label returns the display label for the level
+func (l SuccessLevel) Label() string {
+	switch l {
+	case LevelCritical:
+		return "決定的成功"
+	case LevelSpecial:
+		return "スペシャル"
+	case LevelSuccess:
+		return "成功"
+	case LevelFumble:
+		return "致命的失敗"
+	default:
+		return "失敗"
+	}
+}
+
+// IsSuccess reports whether the level counts as a success
+func (l SuccessLevel) IsSuccess() bool {
+	return l == LevelCritical || l == LevelSpecial || l == LevelSuccess
+}
+
+// Check thresholds
+const (
+	CriticalMax    = 5   // successful rolls of 01-05 are critical
+	FumbleMin      = 96  // failed rolls of 96-00 are fumbles
+	AutoFumble     = 100 // 00 is a fumble whatever the target
+	SpecialDivisor = 5   // rolls at or under target/5 are special
+)
+
+// ResolveCheck returns the success level of a 1D100 roll against target.
+// As with BCDice's CCB, a critical must also succeed and a fumble must also
+// fail: 04 against 3 is a failure and 97 against 99 a success. 00 always
+// fails, so it is a fumble even against a target of 100 or more.
+func ResolveCheck(roll, target int) SuccessLevel {
+	switch {
+	case roll >= AutoFumble:
+		return LevelFumble
+	case roll <= CriticalMax && roll <= target:
+		return LevelCritical
+	case roll >= FumbleMin && roll > target:
+		return LevelFumble
+	case roll <= target/SpecialDivisor:
+		return LevelSpecial
+	case roll <= target:
+		return LevelSuccess
+	default:
+		return LevelFailure
+	}
+}
+
+// MaxCheckLog is the number of checks kept on a character
+const MaxCheckLog = 50
+
+// CheckRoll records a single 1D100 check
+type CheckRoll struct {
+	Label    string       `json:"label"`    // e.g. "目星", "運転(自動車)", "STR×5"
+	Value    int          `json:"value"`    // skill or characteristic value before the modifier
+	Modifier int          `json:"modifier"` // bonus (+) or penalty (-) to the target
+	Roll     int          `json:"roll"`
+	Level    SuccessLevel `json:"level"`
+	Time     time.Time    `json:"time"`
+}
+
+// Target returns the value the roll was made against
+func (c CheckRoll) Target() int {
+	return c.Value + c.Modifier
+}
+
+// RollCheck rolls 1D100 against value+modifier
+func RollCheck(r *dice.Roller, label string, value, modifier int) (CheckRoll, error) {
+	res, err := r.Roll("1D100")
+	if err != nil {
+		return CheckRoll{}, fmt.Errorf("roll check %s: %w", label, err)
+	}
+	return CheckRoll{
+		Label:    label,
+		Value:    value,
+		Modifier: modifier,
+		Roll:     res.Total,
+		Level:    ResolveCheck(res.Total, value+modifier),
+		Time:     time.Now(),
+	}, nil
+}
+
+// AppendChecks appends a check to the log, dropping the oldest beyond MaxCheckLog
+func AppendChecks(log []CheckRoll, c CheckRoll) []CheckRoll {
+	log = append(log, c)
+	if len(log) > MaxCheckLog {
+		log = log[len(log)-MaxCheckLog:]
+	}
+	return log
+}
+
+// SkillValue returns the total of a single skill
+func (s *Status) SkillValue(skills *Skills, key string) (int, bool) {
+	for _, cat := range skills.Categories {
+		if skill, ok := cat.Skills[key]; ok && skill.IsSingle() {
+			return s.SkillInitialValue(key) + skill.Single.Sum(), true
+		}
+	}
+	return 0, false
+}
+
+// GenreValue returns the total and display label of a multi-skill genre
+func (s *Status) GenreValue(skills *Skills, key string, index int) (int, string, bool) {
+	for _, cat := range skills.Categories {
+		skill, ok := cat.Skills[key]
+		if !ok || !skill.IsMulti() || index < 0 || index >= len(skill.Multi.Genres) {
+			continue
+		}
+		genre := skill.Multi.Genres[index]
+		label := key
+		if genre.Label != "" {
+			label = fmt.Sprintf("%s(%s)", key, genre.Label)
+		}
+		return s.SkillInitialValue(key) + genre.Sum(), label, true
+	}
+	return 0, "", false
+}
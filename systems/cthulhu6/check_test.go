@@ -0,0 +1,36 @@
+package cthulhu6
+
+import "testing"
+
+func TestResolveCheck(t *testing.T) {
+	tests := []struct {
+		roll, target int
+		want         SuccessLevel
+	}{
+		{1, 50, LevelCritical},
+		{5, 5, LevelCritical},
+		{5, 0, LevelFailure},
+		{4, 3, LevelFailure},
+		{3, 3, LevelCritical},
+		{6, 50, LevelSpecial},
+		{10, 50, LevelSpecial},
+		{11, 50, LevelSuccess},
+		{50, 50, LevelSuccess},
+		{51, 50, LevelFailure},
+		{95, 99, LevelSuccess},
+		{96, 95, LevelFumble},
+		{96, 99, LevelSuccess},
+		{97, 96, LevelFumble},
+		{100, 99, LevelFumble},
+		{100, 100, LevelFumble},
+		{100, 150, LevelFumble},
+		{99, 150, LevelSuccess},
+		{6, 30, LevelSpecial},
+		{6, 29, LevelSuccess},
+	}
+	for _, tt := range tests {
+		if got := ResolveCheck(tt.roll, tt.target); got != tt.want {
+			t.Errorf("ResolveCheck(%d, %d) = %s, want %s", tt.roll, tt.target, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package cthulhu6
 
 import (
+	"fmt"
+	"slices"
 	"sort"
 
+	"charaxiv/dice"
 	"charaxiv/templates/shared"
 )
 
@@ -16,6 +19,11 @@ func BuildSheetState(pc shared.PageContext, status *Status, skills *Skills) shar
 			Computed:    computed,
 			Parameters:  params,
 			DamageBonus: db,
+			Rolls:       BuildStatRolls(status.Rolls),
+			Generation:  BuildGeneration(status),
+			Rules:       BuildRules(status),
+			Era:         BuildEra(status.Era),
+			Sanity:      BuildSanity(status.Sanity),
 		},
 		Skills: shared.SkillsState{
 			Categories:   skillCategories,
@@ -23,15 +31,176 @@ func BuildSheetState(pc shared.PageContext, status *Status, skills *Skills) shar
 			Extra:        skillExtra,
 			Remaining:    skillPoints,
 		},
+		Occupation: BuildOccupation(skills.Occupation),
+		Warnings:   BuildWarnings(status, skills),
 	}
 }
 
+// BuildStatRolls converts the roll log to template types, newest first
+func BuildStatRolls(log []StatRoll) []shared.StatRoll {
+	rolls := make([]shared.StatRoll, 0, len(log))
+	for i := len(log) - 1; i >= 0; i-- {
+		rolls = append(rolls, shared.StatRoll{
+			Key:    log[i].Key,
+			Detail: log[i].Detail,
+			Value:  log[i].Value,
+		})
+	}
+	return rolls
+}
+
+// BuildGeneration converts the generation settings to template types
+func BuildGeneration(status *Status) shared.StatusGeneration {
+	options := make([]shared.Option, 0, len(GenerationModes))
+	for _, m := range GenerationModes {
+		options = append(options, shared.Option{Value: string(m), Label: m.Label()})
+	}
+	g := shared.StatusGeneration{
+		Mode:    string(status.Generation.Mode),
+		Options: options,
+		CanRoll: status.Generation.CanRoll(),
+		Array:   status.Generation.Array,
+	}
+	if status.Generation.Mode == GenerationPointBuy {
+		g.Budget = status.Generation.Budget
+		g.Used = status.PointsUsed()
+	}
+	return g
+}
+
+// BuildEra converts the era preset selection to template types
+func BuildEra(era Era) shared.StatusEra {
+	out := shared.StatusEra{Era: string(era)}
+	if _, ok := EraByID(era); !ok {
+		out.Era = string(Eras[0].ID)
+	}
+	for _, e := range Eras {
+		out.Options = append(out.Options, shared.Option{Value: string(e.ID), Label: e.Name})
+	}
+	return out
+}
+
+// BuildRules converts the house rules to template types. Skill overrides are
+// listed for every skill of the character's era whose definition references a
+// characteristic, plus any other overridden skill.
+func BuildRules(status *Status) shared.StatusRules {
+	rules := status.Rules
+	out := shared.StatusRules{
+		Profile: string(rules.Profile),
+		Custom:  rules.Profile == RulesCustom,
+	}
+	if out.Profile == "" {
+		out.Profile = string(RulesOfficial)
+	}
+	for _, p := range RuleProfiles {
+		out.Options = append(out.Options, shared.Option{Value: string(p), Label: p.Label()})
+	}
+	rs := rules.Set()
+	for _, key := range ComputedOrder {
+		out.Formulas = append(out.Formulas, shared.RuleFormula{Group: "computed", Key: key, Formula: rs.Computed[key]})
+	}
+	for _, key := range ParameterOrder {
+		out.Formulas = append(out.Formulas, shared.RuleFormula{Group: "parameters", Key: key, Formula: rs.Parameters[key]})
+	}
+	for _, d := range status.era().SkillDefs() {
+		formula, overridden := rs.Skills[d.Key]
+		if !overridden && len(dice.FormulaVariables(d.Initial)) == 0 {
+			continue
+		}
+		if !overridden {
+			formula = d.Initial
+		}
+		out.Formulas = append(out.Formulas, shared.RuleFormula{Group: "skills", Key: d.Key, Formula: formula})
+	}
+	out.Formulas = append(out.Formulas, shared.RuleFormula{Group: "damageBonus", Key: "DB", Formula: FormatDamageBands(rs.DamageBonus)})
+	return out
+}
+
+// BuildCheckRolls converts the check history to template types, newest first
+func BuildCheckRolls(log []CheckRoll) []shared.CheckRoll {
+	checks := make([]shared.CheckRoll, 0, len(log))
+	for i := len(log) - 1; i >= 0; i-- {
+		c := log[i]
+		checks = append(checks, shared.CheckRoll{
+			Label:      c.Label,
+			Target:     c.Target(),
+			Modifier:   c.Modifier,
+			Roll:       c.Roll,
+			Level:      string(c.Level),
+			LevelLabel: c.Level.Label(),
+		})
+	}
+	return checks
+}
+
+// BuildGrowthReports converts growth reports to template types, newest first
+func BuildGrowthReports(reports []GrowthReport) []shared.GrowthReport {
+	out := make([]shared.GrowthReport, 0, len(reports))
+	for i := len(reports) - 1; i >= 0; i-- {
+		r := reports[i]
+		results := make([]shared.GrowthResult, len(r.Results))
+		for j, res := range r.Results {
+			results[j] = shared.GrowthResult{
+				Label:   res.Label,
+				Before:  res.Before,
+				Roll:    res.Roll,
+				Success: res.Success,
+				Gain:    res.Gain,
+				After:   res.After(),
+			}
+		}
+		out = append(out, shared.GrowthReport{
+			Time:    r.Time.Local().Format("2006/01/02 15:04"),
+			Gained:  r.Gained(),
+			Results: results,
+		})
+	}
+	return out
+}
+
+// BuildSanity converts sanity tracking to template types
+func BuildSanity(sanity Sanity) shared.StatusSanity {
+	out := shared.StatusSanity{
+		HasSession:  sanity.SessionStart != nil,
+		SessionLoss: sanity.SessionLoss,
+		Temporary:   sanity.Temporary,
+		Indefinite:  sanity.Indefinite,
+	}
+	if sanity.SessionStart != nil {
+		out.SessionStart = *sanity.SessionStart
+	}
+	for _, k := range InsanityKinds {
+		out.Kinds = append(out.Kinds, shared.Option{Value: string(k), Label: k.Label()})
+	}
+	for _, in := range sanity.Insanities {
+		out.Insanities = append(out.Insanities, shared.Insanity{
+			Label:     in.Label,
+			Kind:      string(in.Kind),
+			KindLabel: in.Kind.Label(),
+		})
+	}
+	for i := len(sanity.Checks) - 1; i >= 0; i-- {
+		c := sanity.Checks[i]
+		out.Checks = append(out.Checks, shared.SanCheck{
+			Expr:       c.Expr,
+			SanBefore:  c.SanBefore,
+			SanAfter:   c.SanAfter(),
+			Roll:       c.Roll,
+			Success:    c.Success,
+			LossDetail: c.LossDetail,
+			Loss:       c.Loss,
+			Temporary:  c.Temporary,
+			Indefinite: c.Indefinite,
+		})
+	}
+	return out
+}
+
 // convertToTemplates converts CoC6 types to template types
 func convertToTemplates(status *Status, skills *Skills) ([]shared.StatusVariable, []shared.ComputedValue, []shared.StatusParameter, string, []shared.SkillCategory, []shared.CustomSkill, shared.SkillExtra, shared.SkillPoints) {
 	// Variables in display order
-	varOrder := []string{"STR", "CON", "POW", "DEX", "APP", "SIZ", "INT", "EDU"}
-	variables := make([]shared.StatusVariable, 0, len(varOrder))
-	for _, key := range varOrder {
+	variables := make([]shared.StatusVariable, 0, len(VariableOrder))
+	for _, key := range VariableOrder {
 		v := status.Variables[key]
 		variables = append(variables, shared.StatusVariable{
 			Key:  key,
@@ -40,14 +209,14 @@ func convertToTemplates(status *Status, skills *Skills) ([]shared.StatusVariable
 			Temp: v.Temp,
 			Min:  v.Min,
 			Max:  v.Max,
+			Dice: v.Dice,
 		})
 	}
 
 	// Computed values in display order
-	computedOrder := []string{"初期SAN", "アイデア", "幸運", "知識", "職業P", "興味P"}
 	computedMap := status.ComputedValues()
-	computed := make([]shared.ComputedValue, 0, len(computedOrder))
-	for _, key := range computedOrder {
+	computed := make([]shared.ComputedValue, 0, len(ComputedOrder))
+	for _, key := range ComputedOrder {
 		computed = append(computed, shared.ComputedValue{
 			Key:   key,
 			Value: computedMap[key],
@@ -55,10 +224,9 @@ func convertToTemplates(status *Status, skills *Skills) ([]shared.StatusVariable
 	}
 
 	// Parameters
-	paramOrder := []string{"HP", "MP", "SAN"}
 	defaults := status.DefaultParameters()
-	parameters := make([]shared.StatusParameter, 0, len(paramOrder))
-	for _, key := range paramOrder {
+	parameters := make([]shared.StatusParameter, 0, len(ParameterOrder))
+	for _, key := range ParameterOrder {
 		var val *int
 		if v := status.Parameters[key]; v != nil {
 			val = v
@@ -170,6 +338,93 @@ func BuildSkill(status *Status, key string, skill Skill) shared.Skill {
 	return templSkill
 }
 
+// BuildOccupation converts the occupation selection and catalog to template types
+func BuildOccupation(sel OccupationSelection) shared.OccupationState {
+	out := shared.OccupationState{Options: make([]shared.Option, len(Occupations))}
+	for i, o := range Occupations {
+		out.Options[i] = shared.Option{Value: o.ID, Label: o.Name}
+	}
+	o, ok := OccupationByID(sel.ID)
+	if !ok {
+		return out
+	}
+	sel = sel.Normalize(o)
+	out.ID = o.ID
+	out.Name = o.Name
+	out.CreditMin, out.CreditMax = o.Credit[0], o.Credit[1]
+	for _, s := range o.Skills {
+		out.Skills = append(out.Skills, s.Label())
+	}
+	for i, choice := range o.Choices {
+		c := shared.OccupationChoice{Count: choice.Count}
+		for j, s := range choice.Skills {
+			c.Picks = append(c.Picks, shared.OccupationPick{
+				Value:    fmt.Sprintf("%d:%d", i, j),
+				Label:    s.Label(),
+				Selected: slices.Contains(sel.Choices[i], j),
+			})
+		}
+		out.Choices = append(out.Choices, c)
+	}
+	return out
+}
+
+// BuildWeapons converts weapons and damage rolls to template types
+func BuildWeapons(status *Status, skills *Skills, weapons []Weapon, damage []DamageRoll) shared.WeaponsState {
+	out := shared.WeaponsState{Catalog: make([]shared.Option, len(WeaponCatalog))}
+	for i, w := range WeaponCatalog {
+		out.Catalog[i] = shared.Option{Value: w.ID, Label: w.Name}
+	}
+	for _, w := range weapons {
+		value, ok := status.WeaponSkillValue(skills, w.Skill)
+		out.Weapons = append(out.Weapons, shared.Weapon{
+			Name:        w.Name,
+			Skill:       w.Skill,
+			SkillValue:  value,
+			HasSkill:    ok,
+			Damage:      w.Damage,
+			DamageExpr:  w.DamageExpr(status),
+			Range:       w.Range,
+			Attacks:     w.Attacks,
+			Ammo:        w.Ammo,
+			Malfunction: w.Malfunction,
+			HP:          w.HP,
+			Melee:       w.Melee,
+		})
+	}
+	for i := len(damage) - 1; i >= 0; i-- {
+		d := damage[i]
+		out.Damage = append(out.Damage, shared.DamageRoll{Weapon: d.Weapon, Detail: d.Detail, Total: d.Total})
+	}
+	return out
+}
+
+// BuildInventory converts items and finances to template types
+func BuildInventory(status *Status, skills *Skills, items []Item, f Finances) shared.InventoryState {
+	credit := IncomeCredit(status, skills)
+	out := shared.InventoryState{
+		Cash:          f.Cash,
+		Assets:        f.Assets,
+		Income:        f.EffectiveIncome(credit),
+		IncomeDerived: f.Income == nil,
+		Credit:        credit,
+	}
+	for _, it := range items {
+		out.Items = append(out.Items, shared.Item{Name: it.Name, Quantity: it.Quantity, Notes: it.Notes, Stored: it.Stored})
+	}
+	return out
+}
+
+// BuildWarnings runs the sheet validation and converts the warnings to template types
+func BuildWarnings(status *Status, skills *Skills) []shared.Warning {
+	ws := ValidateSheet(status, skills)
+	out := make([]shared.Warning, len(ws))
+	for i, w := range ws {
+		out[i] = shared.Warning{Kind: string(w.Kind), Subject: w.Subject, Message: w.Message}
+	}
+	return out
+}
+
 // BuildRemainingPoints creates template SkillPoints from CoC6 data
 func BuildRemainingPoints(status *Status, skills *Skills) shared.SkillPoints {
 	remJob, remHobby := status.RemainingPoints(skills)
@@ -3,11 +3,12 @@ package cthulhu6
 
 // Variable represents an ability score with base, perm, and temp modifiers
 type Variable struct {
-	Base int `json:"base"`
-	Perm int `json:"perm"`
-	Temp int `json:"temp"`
-	Min  int `json:"min"`
-	Max  int `json:"max"`
+	Base int    `json:"base"`
+	Perm int    `json:"perm"`
+	Temp int    `json:"temp"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+	Dice string `json:"dice"` // roll formula for the base, e.g. "3D6"
 }
 
 // Sum returns the total value of the variable
@@ -15,11 +16,19 @@ func (v Variable) Sum() int {
 	return v.Base + v.Perm + v.Temp
 }
 
+// VariableOrder is the display order of ability scores
+var VariableOrder = []string{"STR", "CON", "POW", "DEX", "APP", "SIZ", "INT", "EDU"}
+
 // Status represents the status section for CoC 6th edition
 type Status struct {
 	Variables  map[string]Variable `json:"variables"`
 	Parameters map[string]*int     `json:"parameters"` // nil means use default
 	DB         string              `json:"db"`
+	Rolls      []StatRoll          `json:"rolls"`      // ability score roll log, oldest first
+	Generation Generation          `json:"generation"` // how bases are generated and validated
+	Sanity     Sanity              `json:"sanity"`     // SAN checks and insanities
+	Rules      Rules               `json:"rules"`      // house rules for derived values
+	Era        Era                 `json:"era"`        // era preset selecting the skill list
 }
 
 // NewStatus creates a new status with default values.
@@ -30,74 +39,63 @@ type Status struct {
 func NewStatus() *Status {
 	return &Status{
 		Variables: map[string]Variable{
-			"STR": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18},
-			"CON": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18},
-			"POW": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18},
-			"DEX": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18},
-			"APP": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18},
-			"SIZ": {Base: 13, Perm: 0, Temp: 0, Min: 8, Max: 18},
-			"INT": {Base: 13, Perm: 0, Temp: 0, Min: 8, Max: 18},
-			"EDU": {Base: 14, Perm: 0, Temp: 0, Min: 6, Max: 21},
+			"STR": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18, Dice: "3D6"},
+			"CON": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18, Dice: "3D6"},
+			"POW": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18, Dice: "3D6"},
+			"DEX": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18, Dice: "3D6"},
+			"APP": {Base: 11, Perm: 0, Temp: 0, Min: 3, Max: 18, Dice: "3D6"},
+			"SIZ": {Base: 13, Perm: 0, Temp: 0, Min: 8, Max: 18, Dice: "2D6+6"},
+			"INT": {Base: 13, Perm: 0, Temp: 0, Min: 8, Max: 18, Dice: "2D6+6"},
+			"EDU": {Base: 14, Perm: 0, Temp: 0, Min: 6, Max: 21, Dice: "3D6+3"},
 		},
 		Parameters: map[string]*int{
 			"HP":  nil,
 			"MP":  nil,
 			"SAN": nil,
 		},
-		DB: "",
+		DB:         "",
+		Generation: DefaultGeneration(),
+		Rules:      Rules{Profile: RulesOfficial},
+		Era:        EraModern,
 	}
 }
 
-// ComputedValues returns the derived values from variables
+// ComputedValues returns the derived values from variables under the character's rules
 func (s *Status) ComputedValues() map[string]int {
-	pow := s.Variables["POW"].Sum()
-	inT := s.Variables["INT"].Sum()
-	edu := s.Variables["EDU"].Sum()
-
-	return map[string]int{
-		"初期SAN": pow * 5,
-		"アイデア":  inT * 5,
-		"幸運":    pow * 5,
-		"知識":    edu * 5,
-		"職業P":   edu * 20,
-		"興味P":   inT * 10,
+	rs := s.rules()
+	values := make(map[string]int, len(ComputedOrder))
+	for _, key := range ComputedOrder {
+		values[key] = s.formulaValue(rs.Computed[key])
 	}
+	return values
 }
 
 // DefaultParameters returns the default parameter values derived from variables
 func (s *Status) DefaultParameters() map[string]int {
-	con := s.Variables["CON"].Sum()
-	pow := s.Variables["POW"].Sum()
-	siz := s.Variables["SIZ"].Sum()
-
-	return map[string]int{
-		"HP":  (con + siz + 1) / 2,
-		"MP":  pow,
-		"SAN": pow * 5,
+	rs := s.rules()
+	values := make(map[string]int, len(ParameterOrder))
+	for _, key := range ParameterOrder {
+		values[key] = s.formulaValue(rs.Parameters[key])
 	}
+	return values
 }
 
-// DamageBonus calculates the damage bonus from STR+SIZ
+// DamageBonus returns the override or the band matching STR+SIZ
 func (s *Status) DamageBonus() string {
 	if s.DB != "" {
 		return s.DB
 	}
-	str := s.Variables["STR"].Sum()
-	siz := s.Variables["SIZ"].Sum()
-	sum := str + siz
-
-	switch {
-	case sum < 13:
-		return "-1d6"
-	case sum < 17:
-		return "-1d4"
-	case sum < 25:
+	sum := s.Variables["STR"].Sum() + s.Variables["SIZ"].Sum()
+	bands := s.rules().DamageBonus
+	for _, b := range bands {
+		if b.Max == 0 || sum <= b.Max {
+			return b.Bonus
+		}
+	}
+	if len(bands) == 0 {
 		return "+0"
-	case sum < 33:
-		return "+1d4"
-	default:
-		return "+1d6"
 	}
+	return bands[len(bands)-1].Bonus
 }
 
 // Indefinite calculates the indefinite insanity threshold
@@ -226,170 +224,43 @@ type Skills struct {
 	Categories map[SkillCategory]SkillCategoryData `json:"categories"`
 	Custom     []CustomSkill                       `json:"custom"`
 	Extra      SkillExtra                          `json:"extra"`
+	Occupation OccupationSelection                 `json:"occupation"`
 }
 
-// singleSkill creates a single skill
-func singleSkill(order int) Skill {
-	return Skill{Order: order, Single: &SingleSkill{}}
-}
-
-// multiSkill creates an empty multi-genre skill
-func multiSkill(order int) Skill {
-	return Skill{Order: order, Multi: &MultiSkill{Genres: []SkillGenre{}}}
-}
-
-// multiSkillWithGenre creates a multi-genre skill with one initial genre
-func multiSkillWithGenre(order int, label string) Skill {
-	return Skill{Order: order, Multi: &MultiSkill{Genres: []SkillGenre{{Label: label}}}}
-}
-
-// NewSkills creates skills with default values
+// NewSkills creates skills with default values for the default era
 func NewSkills() *Skills {
-	return &Skills{
-		Categories: map[SkillCategory]SkillCategoryData{
-			SkillCategoryCombat: {
-				Order: 0,
-				Skills: map[string]Skill{
-					"回避":       singleSkill(0),
-					"キック":      singleSkill(1),
-					"組み付き":     singleSkill(2),
-					"こぶし":      singleSkill(3),
-					"頭突き":      singleSkill(4),
-					"投擲":       singleSkill(5),
-					"マーシャルアーツ": singleSkill(6),
-					"拳銃":       singleSkill(7),
-					"サブマシンガン":  singleSkill(8),
-					"ショットガン":   singleSkill(9),
-					"マシンガン":    singleSkill(10),
-					"ライフル":     singleSkill(11),
-				},
-			},
-			SkillCategoryInvestigation: {
-				Order: 1,
-				Skills: map[string]Skill{
-					"目星":    singleSkill(0),
-					"聞き耳":   singleSkill(1),
-					"図書館":   singleSkill(2),
-					"応急手当":  singleSkill(3),
-					"隠れる":   singleSkill(4),
-					"隠す":    singleSkill(5),
-					"変装":    singleSkill(6),
-					"忍び歩き":  singleSkill(7),
-					"追跡":    singleSkill(8),
-					"ナビゲート": singleSkill(9),
-					"写真術":   singleSkill(10),
-					"鍵開け":   singleSkill(11),
-					"精神分析":  singleSkill(12),
-				},
-			},
-			SkillCategoryAction: {
-				Order: 2,
-				Skills: map[string]Skill{
-					"登攀":    singleSkill(0),
-					"跳躍":    singleSkill(1),
-					"運転":    multiSkill(2),
-					"操縦":    multiSkill(3),
-					"重機械操作": singleSkill(4),
-					"機械修理":  singleSkill(5),
-					"電気修理":  singleSkill(6),
-					"製作":    multiSkill(7),
-					"芸術":    multiSkill(8),
-					"乗馬":    singleSkill(9),
-					"水泳":    singleSkill(10),
-				},
-			},
-			SkillCategorySocial: {
-				Order: 3,
-				Skills: map[string]Skill{
-					"言いくるめ": singleSkill(0),
-					"信用":    singleSkill(1),
-					"説得":    singleSkill(2),
-					"値切り":   singleSkill(3),
-				},
-			},
-			SkillCategoryKnowledge: {
-				Order: 4,
-				Skills: map[string]Skill{
-					"クトゥルフ神話": singleSkill(0),
-					"心理学":     singleSkill(1),
-					"母国語":     multiSkillWithGenre(2, ""),
-					"ほかの言語":   multiSkill(3),
-					"オカルト":    singleSkill(4),
-					"歴史":      singleSkill(5),
-					"法律":      singleSkill(6),
-					"経理":      singleSkill(7),
-					"人類学":     singleSkill(8),
-					"考古学":     singleSkill(9),
-					"博物学":     singleSkill(10),
-					"医学":      singleSkill(11),
-					"薬学":      singleSkill(12),
-					"生物学":     singleSkill(13),
-					"化学":      singleSkill(14),
-					"コンピューター": singleSkill(15),
-					"電子工学":    singleSkill(16),
-					"物理学":     singleSkill(17),
-					"天文学":     singleSkill(18),
-					"地質学":     singleSkill(19),
-				},
-			},
-		},
-		Custom: []CustomSkill{},
-		Extra:  SkillExtra{Job: 0, Hobby: 0},
-	}
+	return NewSkillsForEra(EraModern)
 }
 
-// EssentialSkills lists skills that should be shown in bold
-var EssentialSkills = map[string]bool{
-	"回避":   true,
-	"キック":  true,
-	"組み付き": true,
-	"こぶし":  true,
-	"頭突き":  true,
-	"目星":   true,
-	"聞き耳":  true,
-	"図書館":  true,
-	"応急手当": true,
-	"母国語":  true,
-}
+// EssentialSkills lists skills that should be shown in bold in any era
+var EssentialSkills = func() map[string]bool {
+	m := map[string]bool{}
+	for _, e := range Eras {
+		for _, d := range e.SkillDefs() {
+			if d.Essential {
+				m[d.Key] = true
+			}
+		}
+	}
+	return m
+}()
 
 // IsEssentialSkill returns true if the skill is an essential/important skill
 func IsEssentialSkill(skillKey string) bool {
 	return EssentialSkills[skillKey]
 }
 
-// SkillInitialValue returns the initial value for a skill based on character stats
+// SkillInitialValue returns the initial value for a skill based on character stats.
+// Skills without a definition in the character's era (custom skills) start at 1.
 func (s *Status) SkillInitialValue(skillKey string) int {
-	switch skillKey {
-	case "回避":
-		return s.Variables["DEX"].Sum() * 2
-	case "母国語":
-		return s.Variables["EDU"].Sum() * 5
-	case "キック", "組み付き", "投擲", "目星", "聞き耳", "図書館", "跳躍", "水泳", "ライフル":
-		return 25
-	case "こぶし":
-		return 50
-	case "頭突き", "隠れる", "忍び歩き", "追跡", "ナビゲート", "写真術", "電気修理":
-		return 10
-	case "応急手当", "ショットガン":
-		return 30
-	case "隠す", "サブマシンガン", "マシンガン", "信用", "説得":
-		return 15
-	case "登攀":
-		return 40
-	case "運転", "機械修理", "拳銃", "歴史":
-		return 20
-	case "製作", "芸術", "乗馬", "言いくるめ", "値切り", "オカルト", "心理学", "法律", "医学":
-		return 5
-	case "経理", "博物学":
-		return 10
-	case "マーシャルアーツ", "変装", "鍵開け", "精神分析", "操縦", "重機械操作",
-		"ほかの言語", "薬学", "生物学", "化学", "コンピューター", "電子工学", "物理学", "天文学", "人類学", "考古学", "地質学":
-		return 1
-	case "クトゥルフ神話":
-		return 0
-	default:
+	if formula, ok := s.rules().Skills[skillKey]; ok {
+		return s.formulaValue(formula)
+	}
+	d, ok := s.era().SkillDef(skillKey)
+	if !ok {
 		return 1
 	}
+	return s.formulaValue(d.Initial)
 }
 
 // RemainingPoints calculates remaining skill points
@@ -0,0 +1,267 @@
+package cthulhu6
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+//go:embed eras.json
+var erasJSON []byte
+
+// Era selects the skill list of a period setting
+type Era string
+
+const (
+	EraModern   Era = "modern"   // basic rulebook list with the クトゥルフ2010/2015 skills
+	Era1920s    Era = "1920s"    // classic setting without modern technology skills
+	EraGaslight Era = "gaslight" // 1890s, クトゥルフ・バイ・ガスライト
+)
+
+// EraDef is an era preset. Its skills are the built-in skills minus Omit, with
+// each entry of Skills replacing the built-in skill of the same key (base value,
+// category, order) or adding a new one.
+type EraDef struct {
+	ID     Era        `json:"id"`
+	Name   string     `json:"name"`
+	Omit   []string   `json:"omit,omitempty"`
+	Skills []SkillDef `json:"skills,omitempty"`
+
+	defs  []SkillDef // resolved skill list
+	byKey map[string]SkillDef
+}
+
+// SkillDefs returns the era's skill definitions
+func (e EraDef) SkillDefs() []SkillDef {
+	return e.defs
+}
+
+// SkillDef returns the era's definition of a skill
+func (e EraDef) SkillDef(key string) (SkillDef, bool) {
+	d, ok := e.byKey[key]
+	return d, ok
+}
+
+// Eras is the era presets in file order; the first is the default
+var Eras = mustLoadEras()
+
+func mustLoadEras() []EraDef {
+	var eras []EraDef
+	if err := json.Unmarshal(erasJSON, &eras); err != nil {
+		panic(fmt.Sprintf("cthulhu6: parse eras.json: %v", err))
+	}
+	for i := range eras {
+		if err := eras[i].resolve(SkillDefs); err != nil {
+			panic(fmt.Sprintf("cthulhu6: eras.json: %v", err))
+		}
+	}
+	if len(eras) == 0 || eras[0].ID != EraModern {
+		panic("cthulhu6: eras.json: first era must be " + string(EraModern))
+	}
+	return eras
+}
+
+// resolve builds the era's skill list from the built-in skills
+func (e *EraDef) resolve(base []SkillDef) error {
+	if e.ID == "" {
+		return fmt.Errorf("era without id")
+	}
+	replace := make(map[string]SkillDef, len(e.Skills))
+	for _, d := range e.Skills {
+		replace[d.Key] = d
+	}
+	e.defs = make([]SkillDef, 0, len(base)+len(e.Skills))
+	for _, d := range base {
+		if slices.Contains(e.Omit, d.Key) {
+			continue
+		}
+		if r, ok := replace[d.Key]; ok {
+			d = r
+			delete(replace, d.Key)
+		}
+		e.defs = append(e.defs, d)
+	}
+	for _, d := range e.Skills {
+		if _, ok := replace[d.Key]; ok {
+			e.defs = append(e.defs, d)
+		}
+	}
+	for _, key := range e.Omit {
+		if !slices.ContainsFunc(base, func(d SkillDef) bool { return d.Key == key }) {
+			return fmt.Errorf("%s: omits unknown skill %q", e.ID, key)
+		}
+	}
+	if err := validateSkillDefs(e.defs); err != nil {
+		return fmt.Errorf("%s: %w", e.ID, err)
+	}
+	e.byKey = make(map[string]SkillDef, len(e.defs))
+	for _, d := range e.defs {
+		e.byKey[d.Key] = d
+	}
+	return nil
+}
+
+// EraByID returns the era preset with the given ID
+func EraByID(id Era) (EraDef, bool) {
+	for _, e := range Eras {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return EraDef{}, false
+}
+
+// era returns the character's era preset; unset or unknown eras use the default
+func (s *Status) era() EraDef {
+	if e, ok := EraByID(s.Era); ok {
+		return e
+	}
+	return Eras[0]
+}
+
+// isEraSkill reports whether any era defines the skill
+func isEraSkill(key string) bool {
+	for _, e := range Eras {
+		if _, ok := e.SkillDef(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSkillsForEra creates skills with default values from the era's skill definitions
+func NewSkillsForEra(era Era) *Skills {
+	e, ok := EraByID(era)
+	if !ok {
+		e = Eras[0]
+	}
+	skills := &Skills{
+		Categories: make(map[SkillCategory]SkillCategoryData, len(CategoryOrder)),
+		Custom:     []CustomSkill{},
+		Extra:      SkillExtra{Job: 0, Hobby: 0},
+	}
+	for i, cat := range CategoryOrder {
+		skills.Categories[cat] = SkillCategoryData{Order: i, Skills: map[string]Skill{}}
+	}
+	for _, d := range e.defs {
+		skill := Skill{Order: d.Order}
+		if d.Multi {
+			skill.Multi = &MultiSkill{Genres: []SkillGenre{}}
+			for _, label := range d.Genres {
+				skill.Multi.Genres = append(skill.Multi.Genres, SkillGenre{Label: label})
+			}
+		} else {
+			skill.Single = &SingleSkill{}
+		}
+		skills.Categories[d.Category].Skills[d.Key] = skill
+	}
+	return skills
+}
+
+// MigrateSkills rebuilds skills for another era, keeping allocated points.
+// Skills in both eras keep their points under the new era's category and order;
+// a multi skill without genres takes the new era's starting genres.
+// Skills the new era drops become custom skills when they hold points. Custom
+// skills have no initial value, so the old initial value is carried in Perm and
+// the total is unchanged. Custom skills named after a new built-in skill, e.g.
+// "コンピューター" or "運転(自動車)", are folded back into it, less the new
+// initial value, so their totals are kept too.
+func (s *Status) MigrateSkills(skills *Skills, era Era) *Skills {
+	e, ok := EraByID(era)
+	if !ok {
+		e = Eras[0]
+	}
+	next := *s
+	next.Era = e.ID
+	out := NewSkillsForEra(e.ID)
+	out.Extra = skills.Extra
+	out.Occupation = skills.Occupation
+
+	var dropped []CustomSkill
+	for _, cat := range CategoryOrder {
+		catData := skills.Categories[cat]
+		for _, key := range sortedSkillKeys(catData) {
+			skill := catData.Skills[key]
+			d, ok := e.SkillDef(key)
+			if ok && d.Multi == skill.IsMulti() {
+				if skill.IsMulti() && len(skill.Multi.Genres) == 0 {
+					continue // keep the new era's starting genres
+				}
+				skill.Order = d.Order
+				if skill.IsMulti() {
+					skill.Multi = &MultiSkill{Genres: slices.Clone(skill.Multi.Genres)}
+				} else if skill.IsSingle() {
+					single := *skill.Single
+					skill.Single = &single
+				}
+				out.Categories[d.Category].Skills[key] = skill
+				continue
+			}
+			init := s.SkillInitialValue(key)
+			switch {
+			case skill.IsSingle():
+				if sk := skill.Single; sk.Sum() != 0 || sk.Grow {
+					dropped = append(dropped, CustomSkill{Name: key, Job: sk.Job, Hobby: sk.Hobby, Perm: sk.Perm + init, Temp: sk.Temp, Grow: sk.Grow})
+				}
+			case skill.IsMulti():
+				for _, g := range skill.Multi.Genres {
+					if g.Sum() == 0 && !g.Grow {
+						continue
+					}
+					name := key
+					if g.Label != "" {
+						name = fmt.Sprintf("%s(%s)", key, g.Label)
+					}
+					dropped = append(dropped, CustomSkill{Name: name, Job: g.Job, Hobby: g.Hobby, Perm: g.Perm + init, Temp: g.Temp, Grow: g.Grow})
+				}
+			}
+		}
+	}
+
+	for _, cs := range skills.Custom {
+		if !foldCustomSkill(out, &next, e, cs) {
+			out.Custom = append(out.Custom, cs)
+		}
+	}
+	out.Custom = append(out.Custom, dropped...)
+	return out
+}
+
+// foldCustomSkill moves a custom skill into the built-in skill it names,
+// taking the built-in initial value off Perm. A single skill only takes it
+// while it has no points of its own.
+func foldCustomSkill(skills *Skills, status *Status, e EraDef, cs CustomSkill) bool {
+	key, label := cs.Name, ""
+	if base, rest, ok := strings.Cut(cs.Name, "("); ok && strings.HasSuffix(rest, ")") {
+		key, label = base, strings.TrimSuffix(rest, ")")
+	}
+	d, ok := e.SkillDef(key)
+	if !ok {
+		return false
+	}
+	skill := skills.Categories[d.Category].Skills[key]
+	cs.Perm -= status.SkillInitialValue(key)
+	switch {
+	case skill.IsSingle() && label == "":
+		if skill.Single.Sum() != 0 || skill.Single.Grow {
+			return false
+		}
+		skill.Single = &SingleSkill{Job: cs.Job, Hobby: cs.Hobby, Perm: cs.Perm, Temp: cs.Temp, Grow: cs.Grow}
+	case skill.IsMulti():
+		genre := SkillGenre{Label: label, Job: cs.Job, Hobby: cs.Hobby, Perm: cs.Perm, Temp: cs.Temp, Grow: cs.Grow}
+		if i := slices.IndexFunc(skill.Multi.Genres, func(g SkillGenre) bool { return g.Label == label }); i >= 0 {
+			if g := skill.Multi.Genres[i]; g.Sum() != 0 || g.Grow {
+				return false
+			}
+			skill.Multi.Genres[i] = genre
+		} else {
+			skill.Multi.Genres = append(skill.Multi.Genres, genre)
+		}
+	default:
+		return false
+	}
+	skills.Categories[d.Category].Skills[key] = skill
+	return true
+}
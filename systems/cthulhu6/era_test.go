@@ -0,0 +1,146 @@
+package cthulhu6
+
+import "testing"
+
+func TestEraSkillLists(t *testing.T) {
+	modern := NewSkillsForEra(EraModern)
+	if _, ok := modern.Categories[SkillCategoryKnowledge].Skills["コンピューター"]; !ok {
+		t.Error("Expected コンピューター in the modern era")
+	}
+
+	classic := NewSkillsForEra(Era1920s)
+	if _, ok := classic.Categories[SkillCategoryKnowledge].Skills["コンピューター"]; ok {
+		t.Error("Expected no コンピューター in the 1920s")
+	}
+
+	gaslight := NewSkillsForEra(EraGaslight)
+	drive := gaslight.Categories[SkillCategoryAction].Skills["運転"]
+	if !drive.IsMulti() || len(drive.Multi.Genres) != 1 || drive.Multi.Genres[0].Label != "馬車" {
+		t.Errorf("Expected gaslight 運転 to start with 馬車, got %+v", drive)
+	}
+
+	status := NewStatus()
+	status.Era = EraGaslight
+	if got := status.SkillInitialValue("乗馬"); got != 15 {
+		t.Errorf("gaslight 乗馬 = %d, want 15", got)
+	}
+	if got := status.SkillInitialValue("コンピューター"); got != 1 {
+		t.Errorf("gaslight コンピューター = %d, want 1 (custom)", got)
+	}
+	status.Era = ""
+	if got := status.SkillInitialValue("乗馬"); got != 5 {
+		t.Errorf("default 乗馬 = %d, want 5", got)
+	}
+}
+
+func TestEraResolve(t *testing.T) {
+	base := []SkillDef{
+		{Key: "a", Category: SkillCategoryCombat, Order: 0, Initial: "1"},
+		{Key: "b", Category: SkillCategoryCombat, Order: 1, Initial: "1"},
+	}
+	e := EraDef{
+		ID:   "test",
+		Omit: []string{"a"},
+		Skills: []SkillDef{
+			{Key: "b", Category: SkillCategorySocial, Order: 0, Initial: "10"},
+			{Key: "c", Category: SkillCategoryCombat, Order: 0, Initial: "5"},
+		},
+	}
+	if err := e.resolve(base); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if len(e.SkillDefs()) != 2 {
+		t.Fatalf("Expected 2 skills, got %+v", e.SkillDefs())
+	}
+	if d, _ := e.SkillDef("b"); d.Category != SkillCategorySocial || d.Initial != "10" {
+		t.Errorf("Expected b replaced, got %+v", d)
+	}
+	if _, ok := e.SkillDef("a"); ok {
+		t.Error("Expected a omitted")
+	}
+
+	bad := EraDef{ID: "bad", Omit: []string{"z"}}
+	if err := bad.resolve(base); err == nil {
+		t.Error("Expected an error for an unknown omitted skill")
+	}
+}
+
+func TestMigrateSkills(t *testing.T) {
+	skills := NewSkillsForEra(EraModern)
+	knowledge := skills.Categories[SkillCategoryKnowledge].Skills
+	knowledge["コンピューター"] = Skill{Order: knowledge["コンピューター"].Order, Single: &SingleSkill{Job: 40}}
+	knowledge["歴史"] = Skill{Order: knowledge["歴史"].Order, Single: &SingleSkill{Hobby: 10}}
+	combat := skills.Categories[SkillCategoryCombat].Skills
+	combat["マシンガン"] = Skill{Order: combat["マシンガン"].Order, Single: &SingleSkill{Job: 20}}
+	pilot := skills.Categories[SkillCategoryAction].Skills["操縦"]
+	pilot.Multi.Genres = append(pilot.Multi.Genres, SkillGenre{Label: "航空機", Job: 20})
+	skills.Custom = append(skills.Custom, CustomSkill{Name: "料理", Hobby: 5})
+	skills.Extra = SkillExtra{Job: 3}
+	status := NewStatus()
+	totals := map[string]int{}
+	for _, row := range status.SkillRows(skills) {
+		totals[row.Label] = row.Total()
+	}
+
+	gaslight := status.MigrateSkills(skills, EraGaslight)
+	if h := gaslight.Categories[SkillCategoryKnowledge].Skills["歴史"]; h.Single.Hobby != 10 {
+		t.Errorf("Expected 歴史 to keep its points, got %+v", h.Single)
+	}
+	// Dropped skills carry their initial value in Perm, keeping their totals
+	want := []CustomSkill{{Name: "料理", Hobby: 5}, {Name: "マシンガン", Job: 20, Perm: 15}, {Name: "操縦(航空機)", Job: 20, Perm: 1}, {Name: "コンピューター", Job: 40, Perm: 1}}
+	if len(gaslight.Custom) != len(want) {
+		t.Fatalf("Expected custom skills %+v, got %+v", want, gaslight.Custom)
+	}
+	for i, cs := range want {
+		if gaslight.Custom[i] != cs {
+			t.Errorf("custom %d = %+v, want %+v", i, gaslight.Custom[i], cs)
+		}
+	}
+	if gaslight.Extra != skills.Extra {
+		t.Errorf("Expected extra points kept, got %+v", gaslight.Extra)
+	}
+	if pilot.Multi.Genres[0].Job != 20 {
+		t.Error("Migration must not modify the source skills")
+	}
+	for _, cs := range gaslight.Custom {
+		if cs.Total() != totals[cs.Name] {
+			t.Errorf("Expected %s to keep its total %d, got %d", cs.Name, totals[cs.Name], cs.Total())
+		}
+	}
+
+	// Switching back folds the dropped skills into the built-in ones
+	status.Era = EraGaslight
+	modern := status.MigrateSkills(gaslight, EraModern)
+	if c := modern.Categories[SkillCategoryKnowledge].Skills["コンピューター"]; *c.Single != (SingleSkill{Job: 40}) {
+		t.Errorf("Expected コンピューター restored, got %+v", c.Single)
+	}
+	if m := modern.Categories[SkillCategoryCombat].Skills["マシンガン"]; *m.Single != (SingleSkill{Job: 20}) {
+		t.Errorf("Expected マシンガン restored without its carried initial value, got %+v", m.Single)
+	}
+	p := modern.Categories[SkillCategoryAction].Skills["操縦"]
+	if len(p.Multi.Genres) != 1 || p.Multi.Genres[0] != (SkillGenre{Label: "航空機", Job: 20}) {
+		t.Errorf("Expected 操縦(航空機) restored, got %+v", p.Multi.Genres)
+	}
+	if len(modern.Custom) != 1 || modern.Custom[0].Name != "料理" {
+		t.Errorf("Expected only 料理 to stay custom, got %+v", modern.Custom)
+	}
+}
+
+func TestMigrateSkillsFoldsCustomTotals(t *testing.T) {
+	// A hand-made custom skill folded into a built-in skill keeps its total
+	skills := NewSkillsForEra(EraModern)
+	skills.Custom = append(skills.Custom, CustomSkill{Name: "運転(馬車)", Hobby: 30, Perm: 20})
+
+	gaslight := NewStatus().MigrateSkills(skills, EraGaslight)
+	status := NewStatus()
+	status.Era = EraGaslight
+	for _, row := range status.SkillRows(gaslight) {
+		if row.Label == "運転(馬車)" {
+			if row.Category != SkillCategoryAction || row.Total() != 50 || row.Perm != 0 {
+				t.Errorf("Expected 運転(馬車) 20+30=50, got %+v", row)
+			}
+			return
+		}
+	}
+	t.Error("Expected 運転(馬車) folded into 運転")
+}
@@ -0,0 +1,318 @@
+package cthulhu6
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ExternalSheet is a character read from another character sheet service.
+// These formats carry skill totals rather than 職業P/興味P allocations, so
+// each skill's difference from its initial value is recorded as Perm.
+type ExternalSheet struct {
+	Status   *Status
+	Skills   *Skills
+	Memos    map[string]string
+	Unmapped []string // fields and lines that could not be mapped, in input order
+}
+
+// skillAliases maps skill names used by other services to built-in keys
+var skillAliases = map[string]string{
+	"コンピュータ":    "コンピューター",
+	"クトゥルフ神話技能": "クトゥルフ神話",
+	"他の言語":      "ほかの言語",
+	"外国語":       "ほかの言語",
+	"図書館利用":     "図書館",
+	"マーシャル・アーツ": "マーシャルアーツ",
+}
+
+// ignoredChecks are chat palette labels for values derived from characteristics
+var ignoredChecks = []string{"正気度ロール", "SANチェック", "SAN", "アイデア", "幸運", "知識"}
+
+// chatPaletteLine matches a percentile check such as "CCB<=80 【目星】"
+var chatPaletteLine = regexp.MustCompile(`^(?i:CCB|CC|1D100)\s*<=\s*(\d+)\s*(?:【(.+?)】|(\S+))`)
+
+// ParseExternal detects the format of data and parses it. Supported formats
+// are a Charaeno JSON export, a ココフォリア character piece as exported by
+// いあきゃら and other services, and plain chat palette text.
+func ParseExternal(data []byte) (*ExternalSheet, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty input")
+	}
+	if data[0] != '{' {
+		x := newExternalSheet()
+		x.applyChatPalette(string(data))
+		return x, nil
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+	switch {
+	case string(top["kind"]) == `"character"`:
+		return parseCcfolia(top)
+	case top["variables"] != nil:
+		return parseCharaeno(top)
+	}
+	return nil, errors.New("unknown JSON format")
+}
+
+func newExternalSheet() *ExternalSheet {
+	return &ExternalSheet{Status: NewStatus(), Skills: NewSkills(), Memos: map[string]string{}}
+}
+
+// unmapped records a field that could not be mapped
+func (x *ExternalSheet) unmapped(format string, args ...any) {
+	x.Unmapped = append(x.Unmapped, fmt.Sprintf(format, args...))
+}
+
+// parseCharaeno reads a Charaeno export: name, occupation, a variables object
+// holding characteristics, parameters and DB, a skills array of name/value
+// pairs, and a note
+func parseCharaeno(top map[string]json.RawMessage) (*ExternalSheet, error) {
+	var sheet struct {
+		Name       string                     `json:"name"`
+		Occupation string                     `json:"occupation"`
+		Variables  map[string]json.RawMessage `json:"variables"`
+		Skills     []struct {
+			Name  string `json:"name"`
+			Value int    `json:"value"`
+		} `json:"skills"`
+		Note string `json:"note"`
+	}
+	if err := decodeRaw(top, &sheet); err != nil {
+		return nil, fmt.Errorf("parse Charaeno export: %w", err)
+	}
+
+	x := newExternalSheet()
+	for _, key := range sortedKeys(top) {
+		switch key {
+		case "name", "occupation", "variables", "skills", "note":
+		default:
+			x.unmapped("%s", key)
+		}
+	}
+	if sheet.Name != "" {
+		x.unmapped("name: %s", sheet.Name)
+	}
+	x.setOccupation(sheet.Occupation)
+
+	values := map[string]string{}
+	for _, key := range sortedKeys(sheet.Variables) {
+		var s string
+		if json.Unmarshal(sheet.Variables[key], &s) != nil {
+			s = string(sheet.Variables[key])
+		}
+		values[key] = s
+	}
+	x.applyValues(values)
+
+	for _, sk := range sheet.Skills {
+		x.setSkill(sk.Name, sk.Value)
+	}
+	if sheet.Note != "" {
+		x.Memos["public-memo"] = sheet.Note
+	}
+	return x, nil
+}
+
+// parseCcfolia reads a ココフォリア character piece: params hold
+// characteristics, status holds parameters and commands is a chat palette
+func parseCcfolia(top map[string]json.RawMessage) (*ExternalSheet, error) {
+	var piece struct {
+		Data struct {
+			Name   string `json:"name"`
+			Memo   string `json:"memo"`
+			Status []struct {
+				Label string `json:"label"`
+				Value int    `json:"value"`
+			} `json:"status"`
+			Params []struct {
+				Label string `json:"label"`
+				Value string `json:"value"`
+			} `json:"params"`
+			Commands string `json:"commands"`
+		} `json:"data"`
+	}
+	if err := decodeRaw(top, &piece); err != nil {
+		return nil, fmt.Errorf("parse ココフォリア piece: %w", err)
+	}
+
+	x := newExternalSheet()
+	if piece.Data.Name != "" {
+		x.unmapped("name: %s", piece.Data.Name)
+	}
+
+	// Characteristics first, so parameter defaults and skill initial values use them
+	values := map[string]string{}
+	var order []string
+	for _, p := range piece.Data.Params {
+		values[p.Label] = p.Value
+		order = append(order, p.Label)
+	}
+	for _, s := range piece.Data.Status {
+		values[s.Label] = strconv.Itoa(s.Value)
+		order = append(order, s.Label)
+	}
+	x.applyValues(values, order...)
+
+	x.applyChatPalette(piece.Data.Commands)
+	if piece.Data.Memo != "" {
+		x.Memos["public-memo"] = piece.Data.Memo
+	}
+	return x, nil
+}
+
+// decodeRaw decodes a parsed top-level object into a typed value
+func decodeRaw(top map[string]json.RawMessage, out any) error {
+	b, err := json.Marshal(top)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// applyValues maps labelled values onto characteristics, parameters and DB.
+// Derived values that match the computed ones are dropped silently. order
+// lists the labels for the report; it defaults to sorted order.
+func (x *ExternalSheet) applyValues(values map[string]string, order ...string) {
+	if len(order) == 0 {
+		order = sortedKeys(values)
+	}
+	for _, key := range VariableOrder {
+		if s, ok := values[key]; ok {
+			if n, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+				v := x.Status.Variables[key]
+				v.Base = n
+				x.Status.Variables[key] = v
+			}
+		}
+	}
+
+	defaults := x.Status.DefaultParameters()
+	computed := x.Status.ComputedValues()
+	for _, label := range order {
+		s := strings.TrimSpace(values[label])
+		n, err := strconv.Atoi(s)
+		switch {
+		case slices.Contains(VariableOrder, label) && err == nil:
+		case slices.Contains(ParameterOrder, label) && err == nil:
+			if n != defaults[label] {
+				x.Status.Parameters[label] = &n
+			}
+		case label == "DB":
+			if !strings.EqualFold(s, x.Status.DamageBonus()) {
+				x.Status.DB = s
+			}
+		case slices.Contains(ComputedOrder, label) && err == nil && computed[label] == n:
+		default:
+			x.unmapped("%s: %s", label, s)
+		}
+	}
+}
+
+// applyChatPalette maps each percentile check line to a skill
+func (x *ExternalSheet) applyChatPalette(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		m := chatPaletteLine.FindStringSubmatch(line)
+		if m == nil {
+			// Checks against variables, e.g. CCB<={SAN}, are derived values
+			if !strings.Contains(line, "{") {
+				x.unmapped("%s", line)
+			}
+			continue
+		}
+		value, _ := strconv.Atoi(m[1])
+		label := m[2]
+		if label == "" {
+			label = m[3]
+		}
+		if slices.Contains(ignoredChecks, label) || strings.ContainsAny(label, "×*") {
+			continue
+		}
+		x.setSkill(label, value)
+	}
+}
+
+// splitGenre splits a label such as "運転(自動車)" into key and genre
+func splitGenre(label string) (key, genre string) {
+	label = strings.NewReplacer("（", "(", "）", ")").Replace(strings.TrimSpace(label))
+	if i := strings.Index(label, "("); i > 0 && strings.HasSuffix(label, ")") {
+		return label[:i], label[i+1 : len(label)-1]
+	}
+	return label, ""
+}
+
+// setSkill records a skill total, adding genres of multi skills and custom
+// skills for names outside the skill list
+func (x *ExternalSheet) setSkill(label string, value int) {
+	key, genre := splitGenre(label)
+	if alias, ok := skillAliases[key]; ok {
+		key = alias
+	}
+	for cat, data := range x.Skills.Categories {
+		skill, ok := data.Skills[key]
+		if !ok {
+			continue
+		}
+		perm := value - x.Status.SkillInitialValue(key)
+		switch {
+		case skill.IsMulti():
+			i := slices.IndexFunc(skill.Multi.Genres, func(g SkillGenre) bool { return g.Label == genre })
+			if i < 0 {
+				// Fill a blank starting genre before adding one
+				i = slices.IndexFunc(skill.Multi.Genres, func(g SkillGenre) bool { return g == SkillGenre{} })
+			}
+			if i >= 0 {
+				skill.Multi.Genres[i].Label = genre
+			} else {
+				skill.Multi.Genres = append(skill.Multi.Genres, SkillGenre{Label: genre})
+				i = len(skill.Multi.Genres) - 1
+			}
+			skill.Multi.Genres[i].Perm = perm
+		case genre == "":
+			skill.Single.Perm = perm
+		default:
+			continue
+		}
+		data.Skills[key] = skill
+		x.Skills.Categories[cat] = data
+		return
+	}
+	x.Skills.Custom = append(x.Skills.Custom, CustomSkill{Name: label, Perm: value})
+}
+
+// setOccupation selects the catalog occupation with the given name
+func (x *ExternalSheet) setOccupation(name string) {
+	if name == "" {
+		return
+	}
+	for _, o := range Occupations {
+		if o.Name == name {
+			x.Skills.Occupation = OccupationSelection{ID: o.ID}.Normalize(o)
+			return
+		}
+	}
+	x.unmapped("occupation: %s", name)
+}
@@ -0,0 +1,93 @@
+package cthulhu6
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseExternalCcfoliaPiece(t *testing.T) {
+	piece := `{
+		"kind": "character",
+		"data": {
+			"name": "探索者",
+			"memo": "元警官",
+			"status": [{"label": "HP", "value": 9, "max": 13}, {"label": "MP", "value": 12, "max": 12}],
+			"params": [{"label": "STR", "value": "14"}, {"label": "POW", "value": "12"}, {"label": "DB", "value": "+1D4"}, {"label": "幸運", "value": "60"}, {"label": "名声", "value": "3"}],
+			"commands": "CCB<={SAN} 【正気度ロール】\nCCB<=75 【目星】\nCCB<=65 【芸術（写真）】\nCCB<=40 【料理】\nCCB<=({STR}*5) 【STR × 5】\n1d4 【こぶし ダメージ】"
+		}
+	}`
+	x, err := ParseExternal([]byte(piece))
+	if err != nil {
+		t.Fatalf("ParseExternal: %v", err)
+	}
+
+	if got := x.Status.Variables["STR"].Base; got != 14 {
+		t.Errorf("Expected STR 14, got %d", got)
+	}
+	if hp := x.Status.Parameters["HP"]; hp == nil || *hp != 9 {
+		t.Errorf("Expected current HP 9, got %v", hp)
+	}
+	if x.Status.Parameters["MP"] != nil {
+		t.Error("Expected MP at its default to stay derived")
+	}
+	if x.Status.DB != "" {
+		t.Errorf("Expected matching DB to stay derived, got %q", x.Status.DB)
+	}
+	if got := x.Skills.Categories[SkillCategoryInvestigation].Skills["目星"].Single.Perm; got != 50 {
+		t.Errorf("Expected 目星 +50, got %d", got)
+	}
+	art := x.Skills.Categories[SkillCategoryAction].Skills["芸術"].Multi.Genres
+	if i := slices.IndexFunc(art, func(g SkillGenre) bool { return g.Label == "写真" }); i < 0 || art[i].Perm != 60 {
+		t.Errorf("Expected 芸術(写真) +60, got %+v", art)
+	}
+	if len(x.Skills.Custom) != 1 || x.Skills.Custom[0].Name != "料理" || x.Skills.Custom[0].Perm != 40 {
+		t.Errorf("Expected 料理 as a custom skill, got %+v", x.Skills.Custom)
+	}
+	if x.Memos["public-memo"] != "元警官" {
+		t.Errorf("Expected memo, got %q", x.Memos["public-memo"])
+	}
+	want := []string{"name: 探索者", "名声: 3", "1d4 【こぶし ダメージ】"}
+	if !slices.Equal(x.Unmapped, want) {
+		t.Errorf("Unmapped = %q, want %q", x.Unmapped, want)
+	}
+}
+
+func TestParseExternalCharaeno(t *testing.T) {
+	export := `{
+		"name": "探索者",
+		"occupation": "私立探偵",
+		"variables": {"STR": 10, "SIZ": 16, "EDU": 17, "SAN": 50, "DB": "+1D6"},
+		"skills": [{"name": "コンピュータ", "value": 41}, {"name": "母国語(日本語)", "value": 85}],
+		"weapons": []
+	}`
+	x, err := ParseExternal([]byte(export))
+	if err != nil {
+		t.Fatalf("ParseExternal: %v", err)
+	}
+
+	if x.Skills.Occupation.ID != "private-eye" {
+		t.Errorf("Expected 私立探偵 selected, got %q", x.Skills.Occupation.ID)
+	}
+	if x.Status.DB != "+1D6" {
+		t.Errorf("Expected DB override +1D6, got %q", x.Status.DB)
+	}
+	if got := x.Skills.Categories[SkillCategoryKnowledge].Skills["コンピューター"].Single.Perm; got != 40 {
+		t.Errorf("Expected aliased コンピューター +40, got %d", got)
+	}
+	native := x.Skills.Categories[SkillCategoryKnowledge].Skills["母国語"].Multi.Genres
+	if len(native) != 1 || native[0].Label != "日本語" || native[0].Perm != 0 {
+		t.Errorf("Expected the blank 母国語 genre to become 日本語 at EDU×5, got %+v", native)
+	}
+	want := []string{"weapons", "name: 探索者"}
+	if !slices.Equal(x.Unmapped, want) {
+		t.Errorf("Unmapped = %q, want %q", x.Unmapped, want)
+	}
+}
+
+func TestParseExternalRejectsUnknownJSON(t *testing.T) {
+	for _, input := range []string{"", "{", `{"kind":"item"}`} {
+		if _, err := ParseExternal([]byte(input)); err == nil {
+			t.Errorf("ParseExternal(%q): expected an error", input)
+		}
+	}
+}
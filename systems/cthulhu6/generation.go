@@ -0,0 +1,259 @@
+package cthulhu6
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GenerationMode selects how ability scores are generated
+type GenerationMode string
+
+const (
+	// GenerationRoll rolls each variable's own formula (3D6, 2D6+6, 3D6+3)
+	GenerationRoll GenerationMode = "roll"
+	// GenerationRoll4D6 rolls 4D6 and drops the lowest die instead of 3D6
+	GenerationRoll4D6 GenerationMode = "4d6"
+	// GenerationPointBuy distributes a fixed budget across all bases
+	GenerationPointBuy GenerationMode = "pointbuy"
+	// GenerationArray assigns a fixed set of values, swapped between variables
+	GenerationArray GenerationMode = "array"
+)
+
+// GenerationModes lists the modes in display order
+var GenerationModes = []GenerationMode{GenerationRoll, GenerationRoll4D6, GenerationPointBuy, GenerationArray}
+
+// Label returns the display label for the mode
+func (m GenerationMode) Label() string {
+	switch m {
+	case GenerationRoll4D6:
+		return "4D6(最低値を除く)"
+	case GenerationPointBuy:
+		return "ポイント割り振り"
+	case GenerationArray:
+		return "固定値"
+	default:
+		return "ダイス"
+	}
+}
+
+// Defaults for point-buy and fixed-array generation
+const (
+	// DefaultPointBudget is the sum of the expected values used by NewStatus
+	DefaultPointBudget = 95
+)
+
+// DefaultArray is the default fixed array; every value fits every variable's range
+var DefaultArray = []int{15, 14, 13, 12, 11, 10, 9, 8}
+
+var (
+	ErrRollingDisabled = errors.New("rolling is disabled for this generation mode")
+	ErrOverBudget      = errors.New("point budget exceeded")
+	ErrNotInArray      = errors.New("value is not in the fixed array")
+)
+
+// Generation configures how a character's ability scores are generated
+type Generation struct {
+	Mode   GenerationMode `json:"mode"`
+	Budget int            `json:"budget,omitempty"` // total of all bases for point-buy
+	Array  []int          `json:"array,omitempty"`  // values assigned to variables for array mode
+}
+
+// DefaultGeneration returns the standard roll mode
+func DefaultGeneration() Generation {
+	return Generation{Mode: GenerationRoll}
+}
+
+// Normalize validates the mode and fills in missing budget or array values
+func (g Generation) Normalize() (Generation, error) {
+	switch g.Mode {
+	case "":
+		g.Mode = GenerationRoll
+	case GenerationRoll, GenerationRoll4D6:
+	case GenerationPointBuy:
+		if g.Budget <= 0 {
+			g.Budget = DefaultPointBudget
+		}
+	case GenerationArray:
+		if len(g.Array) == 0 {
+			g.Array = slices.Clone(DefaultArray)
+		}
+		if len(g.Array) != len(VariableOrder) {
+			return g, fmt.Errorf("array needs %d values, got %d", len(VariableOrder), len(g.Array))
+		}
+		lo, hi := arrayRange()
+		for _, n := range g.Array {
+			if n < lo || n > hi {
+				return g, fmt.Errorf("array value %d out of range [%d, %d]", n, lo, hi)
+			}
+		}
+	default:
+		return g, fmt.Errorf("unknown generation mode %q", g.Mode)
+	}
+	if g.Mode != GenerationPointBuy {
+		g.Budget = 0
+	}
+	if g.Mode != GenerationArray {
+		g.Array = nil
+	}
+	return g, nil
+}
+
+// arrayRange returns the range every variable accepts, so array values can be swapped freely
+func arrayRange() (lo, hi int) {
+	lo, hi = 0, 1<<31-1
+	for _, v := range NewStatus().Variables {
+		lo = max(lo, v.Min)
+		hi = min(hi, v.Max)
+	}
+	return lo, hi
+}
+
+// CanRoll reports whether the mode generates scores with dice
+func (g Generation) CanRoll() bool {
+	return g.Mode == "" || g.Mode == GenerationRoll || g.Mode == GenerationRoll4D6
+}
+
+// Formula returns the dice formula for a variable under this mode
+func (g Generation) Formula(v Variable) (string, error) {
+	if !g.CanRoll() {
+		return "", ErrRollingDisabled
+	}
+	if g.Mode == GenerationRoll4D6 && strings.HasPrefix(v.Dice, "3D6") {
+		return "4D6DL1" + strings.TrimPrefix(v.Dice, "3D6"), nil
+	}
+	return v.Dice, nil
+}
+
+// PointsUsed returns the total of all bases
+func (s *Status) PointsUsed() int {
+	total := 0
+	for _, v := range s.Variables {
+		total += v.Base
+	}
+	return total
+}
+
+// Validate checks the current bases against the generation rules
+func (s *Status) Validate() error {
+	for _, key := range VariableOrder {
+		v := s.Variables[key]
+		if v.Base < v.Min || v.Base > v.Max {
+			return fmt.Errorf("%s base %d out of range [%d, %d]", key, v.Base, v.Min, v.Max)
+		}
+	}
+	return s.validateGeneration()
+}
+
+// validateGeneration checks the bases against the point budget or fixed array
+func (s *Status) validateGeneration() error {
+	switch s.Generation.Mode {
+	case GenerationPointBuy:
+		if s.PointsUsed() > s.Generation.Budget {
+			return ErrOverBudget
+		}
+	case GenerationArray:
+		bases := make([]int, 0, len(VariableOrder))
+		for _, key := range VariableOrder {
+			bases = append(bases, s.Variables[key].Base)
+		}
+		slices.Sort(bases)
+		array := slices.Clone(s.Generation.Array)
+		slices.Sort(array)
+		if !slices.Equal(bases, array) {
+			return ErrNotInArray
+		}
+	}
+	return nil
+}
+
+// SetBase returns the base changes needed to set key to value under the generation rules.
+// Values are clamped to Min/Max; point-buy clamps further to the remaining budget,
+// and array mode swaps with the variable currently holding value.
+func (s *Status) SetBase(key string, value int) (map[string]int, error) {
+	v, ok := s.Variables[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable %q", key)
+	}
+	value = min(max(value, v.Min), v.Max)
+
+	switch s.Generation.Mode {
+	case GenerationPointBuy:
+		remaining := s.Generation.Budget - s.PointsUsed()
+		value = min(value, v.Base+remaining)
+		if value < v.Min {
+			return nil, ErrOverBudget
+		}
+	case GenerationArray:
+		if !slices.Contains(s.Generation.Array, value) {
+			return nil, ErrNotInArray
+		}
+		if value == v.Base {
+			return map[string]int{}, nil
+		}
+		for _, other := range VariableOrder {
+			o := s.Variables[other]
+			if other == key || o.Base != value {
+				continue
+			}
+			if v.Base < o.Min || v.Base > o.Max {
+				return nil, fmt.Errorf("cannot swap %d into %s: out of range [%d, %d]", v.Base, other, o.Min, o.Max)
+			}
+			return map[string]int{key: value, other: v.Base}, nil
+		}
+		return nil, ErrNotInArray
+	}
+
+	if value == v.Base {
+		return map[string]int{}, nil
+	}
+	return map[string]int{key: value}, nil
+}
+
+// AdjustBase returns the base changes needed to move key by delta.
+// In array mode delta steps through the array values instead of adding.
+func (s *Status) AdjustBase(key string, delta int) (map[string]int, error) {
+	v, ok := s.Variables[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable %q", key)
+	}
+	if s.Generation.Mode != GenerationArray || delta == 0 {
+		return s.SetBase(key, v.Base+delta)
+	}
+
+	values := slices.Clone(s.Generation.Array)
+	slices.Sort(values)
+	values = slices.Compact(values)
+	i, found := slices.BinarySearch(values, v.Base)
+	switch {
+	case delta > 0 && found:
+		i++
+	case delta < 0:
+		i--
+	}
+	if i < 0 || i >= len(values) {
+		return map[string]int{}, nil
+	}
+	return s.SetBase(key, values[i])
+}
+
+// ApplyGeneration returns the bases to use when switching to g.
+// Array mode assigns the array in display order; point-buy keeps the current
+// bases if they fit the budget and otherwise resets each to its Min.
+func (s *Status) ApplyGeneration(g Generation) map[string]int {
+	bases := make(map[string]int, len(VariableOrder))
+	switch g.Mode {
+	case GenerationArray:
+		for i, key := range VariableOrder {
+			bases[key] = g.Array[i]
+		}
+	case GenerationPointBuy:
+		if s.PointsUsed() > g.Budget {
+			for _, key := range VariableOrder {
+				bases[key] = s.Variables[key].Min
+			}
+		}
+	}
+	return bases
+}
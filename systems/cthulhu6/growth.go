@@ -0,0 +1,154 @@
+package cthulhu6
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"charaxiv/dice"
+)
+
+// MaxGrowthReports is the number of growth reports kept on a character
+const MaxGrowthReports = 20
+
+// GrowthResult is the outcome of one skill's growth check
+type GrowthResult struct {
+	Label   string `json:"label"`
+	Before  int    `json:"before"` // total at the time of the check
+	Roll    int    `json:"roll"`   // 1D100
+	Success bool   `json:"success"`
+	Gain    int    `json:"gain"` // 1D10 added to Perm on success
+}
+
+// After returns the total after growth
+func (g GrowthResult) After() int {
+	return g.Before + g.Gain
+}
+
+// GrowthReport records a single growth resolution for keeper review
+type GrowthReport struct {
+	Time    time.Time      `json:"time"`
+	Results []GrowthResult `json:"results"`
+}
+
+// Gained returns the number of skills that grew
+func (r GrowthReport) Gained() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Success {
+			n++
+		}
+	}
+	return n
+}
+
+// rollGrowth rolls a growth check: 1D100 above the current total succeeds and adds 1D10
+func rollGrowth(r *dice.Roller, label string, total int) (GrowthResult, error) {
+	check, err := r.Roll("1D100")
+	if err != nil {
+		return GrowthResult{}, fmt.Errorf("growth %s: %w", label, err)
+	}
+	res := GrowthResult{Label: label, Before: total, Roll: check.Total, Success: check.Total > total}
+	if res.Success {
+		gain, err := r.Roll("1D10")
+		if err != nil {
+			return GrowthResult{}, fmt.Errorf("growth %s: %w", label, err)
+		}
+		res.Gain = gain.Total
+	}
+	return res, nil
+}
+
+// GrowthChanges lists what ResolveGrowth modified
+type GrowthChanges struct {
+	Skills map[SkillCategory][]string // changed skill keys by category
+	Custom bool                       // custom skills changed
+}
+
+// ResolveGrowth rolls growth for every skill, genre and custom skill marked Grow,
+// adds gains to Perm and clears the flags. Skills are modified in place.
+func ResolveGrowth(r *dice.Roller, status *Status, skills *Skills) (GrowthReport, GrowthChanges, error) {
+	report := GrowthReport{Time: time.Now()}
+	changed := GrowthChanges{Skills: make(map[SkillCategory][]string)}
+
+	for _, cat := range CategoryOrder {
+		catData := skills.Categories[cat]
+		keys := make([]string, 0, len(catData.Skills))
+		for key := range catData.Skills {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return catData.Skills[keys[i]].Order < catData.Skills[keys[j]].Order
+		})
+
+		for _, key := range keys {
+			skill := catData.Skills[key]
+			init := status.SkillInitialValue(key)
+			grew := false
+			switch {
+			case skill.IsSingle() && skill.Single.Grow:
+				res, err := rollGrowth(r, key, init+skill.Single.Sum())
+				if err != nil {
+					return GrowthReport{}, GrowthChanges{}, err
+				}
+				skill.Single.Perm += res.Gain
+				skill.Single.Grow = false
+				report.Results = append(report.Results, res)
+				grew = true
+			case skill.IsMulti():
+				for i := range skill.Multi.Genres {
+					g := &skill.Multi.Genres[i]
+					if !g.Grow {
+						continue
+					}
+					label := key
+					if g.Label != "" {
+						label = fmt.Sprintf("%s(%s)", key, g.Label)
+					}
+					res, err := rollGrowth(r, label, init+g.Sum())
+					if err != nil {
+						return GrowthReport{}, GrowthChanges{}, err
+					}
+					g.Perm += res.Gain
+					g.Grow = false
+					report.Results = append(report.Results, res)
+					grew = true
+				}
+			}
+			if grew {
+				catData.Skills[key] = skill
+				changed.Skills[cat] = append(changed.Skills[cat], key)
+			}
+		}
+	}
+
+	for i := range skills.Custom {
+		cs := &skills.Custom[i]
+		if !cs.Grow {
+			continue
+		}
+		label := cs.Name
+		if label == "" {
+			label = "無名"
+		}
+		res, err := rollGrowth(r, label, cs.Total())
+		if err != nil {
+			return GrowthReport{}, GrowthChanges{}, err
+		}
+		cs.Perm += res.Gain
+		cs.Grow = false
+		report.Results = append(report.Results, res)
+		changed.Custom = true
+	}
+
+	return report, changed, nil
+}
+
+// AppendGrowthReport appends a report, dropping the oldest beyond MaxGrowthReports
+func AppendGrowthReport(reports []GrowthReport, report GrowthReport) []GrowthReport {
+	reports = append(reports, report)
+	if len(reports) > MaxGrowthReports {
+		reports = reports[len(reports)-MaxGrowthReports:]
+	}
+	return reports
+}
@@ -0,0 +1,136 @@
+package cthulhu6
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Item is one entry of the inventory
+type Item struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Notes    string `json:"notes"`
+	Stored   bool   `json:"stored"` // kept at home or in storage rather than carried
+}
+
+// Set updates one field from a form value. Quantity must be a non-negative integer;
+// stored accepts "true"/"on".
+func (it *Item) Set(field, value string) error {
+	switch field {
+	case "name":
+		it.Name = strings.TrimSpace(value)
+	case "quantity":
+		n, err := parseAmount(value)
+		if err != nil {
+			return fmt.Errorf("item quantity: %w", err)
+		}
+		it.Quantity = n
+	case "notes":
+		it.Notes = value
+	case "stored":
+		it.Stored = value == "true" || value == "on"
+	default:
+		return fmt.Errorf("unknown item field %q", field)
+	}
+	return nil
+}
+
+// MoveItem moves the item at index by delta positions, clamped to the list bounds.
+// It reports whether the order changed.
+func MoveItem(items []Item, index, delta int) bool {
+	if index < 0 || index >= len(items) {
+		return false
+	}
+	to := min(max(index+delta, 0), len(items)-1)
+	if to == index {
+		return false
+	}
+	it := items[index]
+	if to > index {
+		copy(items[index:to], items[index+1:to+1])
+	} else {
+		copy(items[to+1:index+1], items[to:index])
+	}
+	items[to] = it
+	return true
+}
+
+// Finances tracks money in the era's currency
+type Finances struct {
+	Cash   int  `json:"cash"`
+	Assets int  `json:"assets"`
+	Income *int `json:"income"` // yearly income; nil uses the value derived from 信用
+}
+
+// Set updates one field from a form value. An empty income clears the override.
+func (f *Finances) Set(field, value string) error {
+	if field == "income" && strings.TrimSpace(value) == "" {
+		f.Income = nil
+		return nil
+	}
+	n, err := parseAmount(value)
+	if err != nil {
+		return fmt.Errorf("finances %s: %w", field, err)
+	}
+	switch field {
+	case "cash":
+		f.Cash = n
+	case "assets":
+		f.Assets = n
+	case "income":
+		f.Income = &n
+	default:
+		return fmt.Errorf("unknown finances field %q", field)
+	}
+	return nil
+}
+
+// EffectiveIncome returns the income override or the value derived from credit
+func (f Finances) EffectiveIncome(credit int) int {
+	if f.Income != nil {
+		return *f.Income
+	}
+	return DerivedIncome(credit)
+}
+
+// IncomeCredit returns the 信用 yearly income is derived from: the skill
+// value, held within the selected occupation's 信用 range
+func IncomeCredit(status *Status, skills *Skills) int {
+	credit, _ := status.SkillValue(skills, "信用")
+	if o, ok := OccupationByID(skills.Occupation.ID); ok {
+		credit = min(max(credit, o.Credit[0]), o.Credit[1])
+	}
+	return credit
+}
+
+// DerivedIncome returns the yearly income for a 信用 value
+func DerivedIncome(credit int) int {
+	switch {
+	case credit <= 0:
+		return 0
+	case credit < 10:
+		return 500
+	case credit < 50:
+		return credit * 100
+	case credit < 90:
+		return credit * 200
+	case credit < 99:
+		return credit * 1000
+	default:
+		return 250000
+	}
+}
+
+// parseAmount parses a non-negative integer, ignoring thousands separators
+func parseAmount(value string) (int, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q must be a non-negative integer", value)
+	}
+	return n, nil
+}
@@ -0,0 +1,79 @@
+package cthulhu6
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMoveItem(t *testing.T) {
+	names := func(items []Item) []string {
+		out := make([]string, len(items))
+		for i, it := range items {
+			out[i] = it.Name
+		}
+		return out
+	}
+	tests := []struct {
+		index, delta int
+		want         []string
+		moved        bool
+	}{
+		{0, 1, []string{"b", "a", "c", "d"}, true},
+		{3, -1, []string{"a", "b", "d", "c"}, true},
+		{0, 3, []string{"b", "c", "d", "a"}, true},
+		{3, -9, []string{"d", "a", "b", "c"}, true},
+		{0, -1, []string{"a", "b", "c", "d"}, false},
+		{3, 1, []string{"a", "b", "c", "d"}, false},
+		{4, -1, []string{"a", "b", "c", "d"}, false},
+	}
+	for _, tt := range tests {
+		items := []Item{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}
+		moved := MoveItem(items, tt.index, tt.delta)
+		if moved != tt.moved || !slices.Equal(names(items), tt.want) {
+			t.Errorf("MoveItem(%d, %d) = %v %v, want %v %v", tt.index, tt.delta, moved, names(items), tt.moved, tt.want)
+		}
+	}
+}
+
+func TestFinancesSet(t *testing.T) {
+	var f Finances
+	if err := f.Set("cash", "1,200"); err != nil || f.Cash != 1200 {
+		t.Errorf("Expected cash 1200, got %d (%v)", f.Cash, err)
+	}
+	for field, value := range map[string]string{"cash": "-1", "assets": "abc", "debt": "1"} {
+		if err := f.Set(field, value); err == nil {
+			t.Errorf("Expected %s=%q to be rejected", field, value)
+		}
+	}
+
+	if got := f.EffectiveIncome(15); got != DerivedIncome(15) {
+		t.Errorf("Expected derived income, got %d", got)
+	}
+	f.Set("income", "3000")
+	if got := f.EffectiveIncome(15); got != 3000 {
+		t.Errorf("Expected income override 3000, got %d", got)
+	}
+	f.Set("income", "")
+	if f.Income != nil {
+		t.Error("Expected empty income to clear the override")
+	}
+}
+
+func TestIncomeCredit(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	if got := IncomeCredit(status, skills); got != 15 {
+		t.Errorf("Expected 信用 15 without an occupation, got %d", got)
+	}
+
+	skills.Occupation = OccupationSelection{ID: "dilettante"} // 信用 50-99
+	if got := IncomeCredit(status, skills); got != 50 {
+		t.Errorf("Expected 信用 raised to the occupation's 50, got %d", got)
+	}
+
+	skills.Occupation = OccupationSelection{ID: "private-eye"} // 信用 9-30
+	credit := skills.Categories[SkillCategorySocial].Skills["信用"]
+	credit.Single.Job = 35 // 15 + 35 = 50
+	if got := IncomeCredit(status, skills); got != 30 {
+		t.Errorf("Expected 信用 held to the occupation's 30, got %d", got)
+	}
+}
@@ -0,0 +1,106 @@
+package cthulhu6
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+//go:embed occupations.json
+var occupationsJSON []byte
+
+// OccupationSkill is a skill an occupation may spend 職業P on
+type OccupationSkill struct {
+	Key      string `json:"key"`
+	Genre    string `json:"genre,omitempty"`    // fixed genre of a multi skill, e.g. "自動車" for 運転
+	AnyGenre bool   `json:"anyGenre,omitempty"` // any one genre of a multi skill, e.g. 芸術(任意)
+}
+
+// Label returns the display label, e.g. "目星", "運転(自動車)", "芸術(任意)"
+func (o OccupationSkill) Label() string {
+	switch {
+	case o.Genre != "":
+		return fmt.Sprintf("%s(%s)", o.Key, o.Genre)
+	case o.AnyGenre:
+		return o.Key + "(任意)"
+	default:
+		return o.Key
+	}
+}
+
+// OccupationChoice is a "choose Count from Skills" slot
+type OccupationChoice struct {
+	Count  int               `json:"count"`
+	Skills []OccupationSkill `json:"skills"`
+}
+
+// Occupation is a catalog entry
+type Occupation struct {
+	ID      string             `json:"id"`
+	Name    string             `json:"name"`
+	Credit  [2]int             `json:"credit"` // allowed 信用 range, inclusive
+	Skills  []OccupationSkill  `json:"skills"`
+	Choices []OccupationChoice `json:"choices"`
+}
+
+// Occupations is the occupation catalog in display order
+var Occupations = mustLoadOccupations()
+
+func mustLoadOccupations() []Occupation {
+	var occupations []Occupation
+	if err := json.Unmarshal(occupationsJSON, &occupations); err != nil {
+		panic(fmt.Sprintf("cthulhu6: parse occupations.json: %v", err))
+	}
+	return occupations
+}
+
+// OccupationByID returns the catalog entry with the given ID
+func OccupationByID(id string) (Occupation, bool) {
+	for _, o := range Occupations {
+		if o.ID == id {
+			return o, true
+		}
+	}
+	return Occupation{}, false
+}
+
+// OccupationSelection records the chosen occupation and its "choose N" picks
+type OccupationSelection struct {
+	ID      string  `json:"id"`
+	Choices [][]int `json:"choices"` // picked option indices per choice slot
+}
+
+// Normalize drops out-of-range and duplicate picks and caps each slot at its Count
+func (sel OccupationSelection) Normalize(o Occupation) OccupationSelection {
+	out := OccupationSelection{ID: o.ID, Choices: make([][]int, len(o.Choices))}
+	for i, choice := range o.Choices {
+		out.Choices[i] = []int{}
+		if i >= len(sel.Choices) {
+			continue
+		}
+		for _, idx := range sel.Choices[i] {
+			if idx < 0 || idx >= len(choice.Skills) || slices.Contains(out.Choices[i], idx) {
+				continue
+			}
+			if len(out.Choices[i]) == choice.Count {
+				break
+			}
+			out.Choices[i] = append(out.Choices[i], idx)
+		}
+		slices.Sort(out.Choices[i])
+	}
+	return out
+}
+
+// Allowed returns the fixed occupation skills plus the picked ones
+func (o Occupation) Allowed(sel OccupationSelection) []OccupationSkill {
+	allowed := slices.Clone(o.Skills)
+	sel = sel.Normalize(o)
+	for i, picks := range sel.Choices {
+		for _, idx := range picks {
+			allowed = append(allowed, o.Choices[i].Skills[idx])
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,135 @@
+package cthulhu6
+
+import "testing"
+
+func TestOccupationCatalog(t *testing.T) {
+	skills := NewSkills()
+	kinds := map[string]bool{} // true for multi skills
+	for _, cat := range skills.Categories {
+		for key, skill := range cat.Skills {
+			kinds[key] = skill.IsMulti()
+		}
+	}
+	check := func(o Occupation, s OccupationSkill) {
+		multi, ok := kinds[s.Key]
+		if !ok {
+			t.Errorf("%s: unknown skill %q", o.ID, s.Key)
+			return
+		}
+		if (s.Genre != "" || s.AnyGenre) && !multi {
+			t.Errorf("%s: %s has a genre but is not a multi skill", o.ID, s.Label())
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, o := range Occupations {
+		if o.ID == "" || o.Name == "" || seen[o.ID] {
+			t.Errorf("Invalid or duplicate occupation %+v", o)
+		}
+		seen[o.ID] = true
+		if o.Credit[0] > o.Credit[1] {
+			t.Errorf("%s: credit range %v is inverted", o.ID, o.Credit)
+		}
+		for _, s := range o.Skills {
+			check(o, s)
+		}
+		for _, c := range o.Choices {
+			if c.Count < 1 || c.Count > len(c.Skills) {
+				t.Errorf("%s: choose %d from %d", o.ID, c.Count, len(c.Skills))
+			}
+			for _, s := range c.Skills {
+				check(o, s)
+			}
+		}
+	}
+}
+
+func TestOccupationSelectionNormalize(t *testing.T) {
+	o, _ := OccupationByID("professor") // choose 3 from 12
+	sel := OccupationSelection{ID: "professor", Choices: [][]int{{5, 1, 1, -1, 99, 0, 3}}}.Normalize(o)
+	if got := sel.Choices[0]; len(got) != 3 || got[0] != 0 || got[1] != 1 || got[2] != 5 {
+		t.Errorf("Expected [0 1 5], got %v", got)
+	}
+}
+
+func TestValidateSheetOccupation(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	skills.Occupation = OccupationSelection{ID: "private-eye", Choices: [][]int{{0}}} // 拳銃
+
+	setJob := func(cat SkillCategory, key string, job int) {
+		s := skills.Categories[cat].Skills[key]
+		s.Single.Job = job
+	}
+	setJob(SkillCategoryInvestigation, "目星", 10)
+	setJob(SkillCategoryCombat, "拳銃", 10)
+	if ws := ValidateSheet(status, skills); len(ws) != 0 {
+		t.Fatalf("Expected no warnings, got %+v", ws)
+	}
+
+	setJob(SkillCategoryCombat, "ライフル", 10)
+	art := skills.Categories[SkillCategoryAction].Skills["芸術"]
+	art.Multi.Genres = []SkillGenre{{Label: "絵画", Job: 5}}
+	skills.Categories[SkillCategoryAction].Skills["芸術"] = art
+	skills.Occupation.Choices = nil
+
+	got := map[string]WarningKind{}
+	for _, w := range ValidateSheet(status, skills) {
+		got[w.Subject] = w.Kind
+	}
+	want := map[string]WarningKind{
+		"拳銃":     WarningNotOccupation,
+		"ライフル":   WarningNotOccupation,
+		"芸術(絵画)": WarningNotOccupation,
+		"":       WarningOccupationPick,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	for subject, kind := range want {
+		if got[subject] != kind {
+			t.Errorf("%q: expected %s, got %s", subject, kind, got[subject])
+		}
+	}
+}
+
+func TestValidateSheetOccupationGenres(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	skills.Occupation = OccupationSelection{ID: "dilettante", Choices: [][]int{{0, 1}}}
+	credit := skills.Categories[SkillCategorySocial].Skills["信用"]
+	credit.Single.Job = 35 // 15 + 35 = 50
+
+	action := skills.Categories[SkillCategoryAction]
+	drive := action.Skills["運転"]
+	drive.Multi.Genres = []SkillGenre{{Label: "自動車", Job: 10}, {Label: "二輪車", Job: 10}}
+	action.Skills["運転"] = drive
+	art := action.Skills["芸術"]
+	art.Multi.Genres = []SkillGenre{{Label: "絵画", Job: 10}, {Label: "音楽", Job: 10}}
+	action.Skills["芸術"] = art
+
+	got := map[string]WarningKind{}
+	for _, w := range ValidateSheet(status, skills) {
+		got[w.Subject] = w.Kind
+	}
+	// 運転 only allows 自動車; 芸術(任意) allows one genre
+	want := map[string]WarningKind{
+		"運転(二輪車)": WarningNotOccupation,
+		"芸術(音楽)":  WarningNotOccupation,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	for subject, kind := range want {
+		if got[subject] != kind {
+			t.Errorf("%q: expected %s, got %s", subject, kind, got[subject])
+		}
+	}
+
+	credit.Single.Job = 0
+	found := false
+	for _, w := range ValidateSheet(status, skills) {
+		found = found || w.Kind == WarningCreditRange
+	}
+	if !found {
+		t.Error("Expected a credit range warning for 信用 15")
+	}
+}
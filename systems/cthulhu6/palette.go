@@ -0,0 +1,179 @@
+package cthulhu6
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SkillRow is one skill line of the sheet with its point breakdown
+type SkillRow struct {
+	Category SkillCategory // empty for custom skills
+	Label    string        // e.g. "目星", "運転(自動車)"
+	Init     int
+	Job      int
+	Hobby    int
+	Perm     int
+	Temp     int
+}
+
+// Total returns the skill's value
+func (r SkillRow) Total() int {
+	return r.Init + r.Job + r.Hobby + r.Perm + r.Temp
+}
+
+// SkillRows returns every skill in sheet order: categories in display order,
+// single skills before multi skills, then named custom skills
+func (s *Status) SkillRows(skills *Skills) []SkillRow {
+	var out []SkillRow
+	for _, cat := range CategoryOrder {
+		keys := make([]string, 0, len(skills.Categories[cat].Skills))
+		for key := range skills.Categories[cat].Skills {
+			keys = append(keys, key)
+		}
+		all := skills.Categories[cat].Skills
+		sort.Slice(keys, func(i, j int) bool {
+			a, b := all[keys[i]], all[keys[j]]
+			if a.IsMulti() != b.IsMulti() {
+				return b.IsMulti()
+			}
+			return a.Order < b.Order
+		})
+		for _, key := range keys {
+			skill, init := all[key], s.SkillInitialValue(key)
+			if skill.IsSingle() {
+				sk := skill.Single
+				out = append(out, SkillRow{Category: cat, Label: key, Init: init, Job: sk.Job, Hobby: sk.Hobby, Perm: sk.Perm, Temp: sk.Temp})
+				continue
+			}
+			for _, g := range skill.Multi.Genres {
+				label := key
+				if g.Label != "" {
+					label = fmt.Sprintf("%s(%s)", key, g.Label)
+				}
+				out = append(out, SkillRow{Category: cat, Label: label, Init: init, Job: g.Job, Hobby: g.Hobby, Perm: g.Perm, Temp: g.Temp})
+			}
+		}
+	}
+	for _, cs := range skills.Custom {
+		if cs.Name != "" {
+			out = append(out, SkillRow{Label: cs.Name, Job: cs.Job, Hobby: cs.Hobby, Perm: cs.Perm, Temp: cs.Temp})
+		}
+	}
+	return out
+}
+
+// PaletteSkill is a skill total labelled for a chat palette
+type PaletteSkill struct {
+	Label string // e.g. "目星", "運転(自動車)"
+	Value int
+}
+
+// PaletteSkills returns every skill total in sheet order
+func (s *Status) PaletteSkills(skills *Skills) []PaletteSkill {
+	rows := s.SkillRows(skills)
+	out := make([]PaletteSkill, len(rows))
+	for i, row := range rows {
+		out[i] = PaletteSkill{Label: row.Label, Value: row.Total()}
+	}
+	return out
+}
+
+// ChatPalette returns a BCDice chat palette for the Cthulhu system: the SAN
+// check against the live {SAN} value, アイデア/幸運/知識, characteristic ×5
+// rolls, every skill check and melee damage with the damage bonus
+func ChatPalette(status *Status, skills *Skills, weapons []Weapon) string {
+	var b strings.Builder
+	line := func(command, label string) {
+		fmt.Fprintf(&b, "%s 【%s】\n", command, label)
+	}
+
+	line("CCB<={SAN}", "正気度ロール")
+	computed := status.ComputedValues()
+	for _, key := range []string{"アイデア", "幸運", "知識"} {
+		line("CCB<="+strconv.Itoa(computed[key]), key)
+	}
+	b.WriteString("\n")
+
+	for _, key := range VariableOrder {
+		line("CCB<="+strconv.Itoa(status.Variables[key].Sum()*5), key+" × 5")
+	}
+	b.WriteString("\n")
+
+	for _, sk := range status.PaletteSkills(skills) {
+		line("CCB<="+strconv.Itoa(sk.Value), sk.Label)
+	}
+
+	if len(weapons) > 0 {
+		b.WriteString("\n")
+		for _, w := range weapons {
+			if w.Damage != "" {
+				line(w.DamageExpr(status), w.Name+" ダメージ")
+			}
+		}
+	}
+	return b.String()
+}
+
+// CcfoliaCharacter is a ココフォリア character piece in clipboard format
+type CcfoliaCharacter struct {
+	Kind string      `json:"kind"` // always "character"
+	Data CcfoliaData `json:"data"`
+}
+
+// CcfoliaData is the body of a ココフォリア character piece
+type CcfoliaData struct {
+	Name        string          `json:"name"`
+	Memo        string          `json:"memo"`
+	Initiative  int             `json:"initiative"`
+	ExternalURL string          `json:"externalUrl"`
+	Status      []CcfoliaStatus `json:"status"`
+	Params      []CcfoliaParam  `json:"params"`
+	Commands    string          `json:"commands"`
+}
+
+// CcfoliaStatus is a status bar with a current and maximum value
+type CcfoliaStatus struct {
+	Label string `json:"label"`
+	Value int    `json:"value"`
+	Max   int    `json:"max"`
+}
+
+// CcfoliaParam is a named value that commands can reference as {label}
+type CcfoliaParam struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// MaxSanity returns the SAN ceiling: 99 minus クトゥルフ神話
+func (s *Status) MaxSanity(skills *Skills) int {
+	mythos, _ := s.SkillValue(skills, "クトゥルフ神話")
+	return 99 - mythos
+}
+
+// Ccfolia returns the character as a ココフォリア piece: HP/MP/SAN bars,
+// characteristics and DB as params, the chat palette as commands and DEX as
+// initiative
+func Ccfolia(name, memo, url string, status *Status, skills *Skills, weapons []Weapon) CcfoliaCharacter {
+	data := CcfoliaData{
+		Name:        name,
+		Memo:        memo,
+		Initiative:  status.Variables["DEX"].Sum(),
+		ExternalURL: url,
+		Commands:    ChatPalette(status, skills, weapons),
+	}
+
+	maxima := status.DefaultParameters()
+	maxima["SAN"] = status.MaxSanity(skills)
+	for _, key := range ParameterOrder {
+		data.Status = append(data.Status, CcfoliaStatus{Label: key, Value: status.EffectiveParameter(key), Max: maxima[key]})
+	}
+
+	for _, key := range VariableOrder {
+		data.Params = append(data.Params, CcfoliaParam{Label: key, Value: strconv.Itoa(status.Variables[key].Sum())})
+	}
+	data.Params = append(data.Params, CcfoliaParam{Label: "DB", Value: status.DamageBonus()})
+
+	return CcfoliaCharacter{Kind: "character", Data: data}
+}
@@ -0,0 +1,58 @@
+package cthulhu6
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChatPalette(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	spot := skills.Categories[SkillCategoryInvestigation].Skills["目星"]
+	spot.Single.Job = 50
+	drive := skills.Categories[SkillCategoryAction].Skills["運転"]
+	drive.Multi.Genres = append(drive.Multi.Genres, SkillGenre{Label: "自動車", Hobby: 30})
+	skills.Custom = append(skills.Custom, CustomSkill{Name: "料理", Perm: 40})
+	weapons := []Weapon{{Name: "こぶし", Damage: "1D3", Melee: true}, {Name: "拳銃", Damage: "1D10"}}
+	status.Variables["STR"] = Variable{Base: 16, Min: 3, Max: 18}
+
+	palette := ChatPalette(status, skills, weapons)
+	for _, want := range []string{
+		"CCB<={SAN} 【正気度ロール】\n",
+		"CCB<=65 【アイデア】\n",
+		"CCB<=80 【STR × 5】\n",
+		"CCB<=75 【目星】\n",
+		"CCB<=50 【運転(自動車)】\n",
+		"CCB<=40 【料理】\n",
+		"1D3+1d4 【こぶし ダメージ】\n",
+		"1D10 【拳銃 ダメージ】\n",
+	} {
+		if !strings.Contains(palette, want) {
+			t.Errorf("Expected palette to contain %q", want)
+		}
+	}
+	if strings.Index(palette, "【回避】") > strings.Index(palette, "【目星】") {
+		t.Error("Expected 戦闘技能 before 探索技能")
+	}
+}
+
+func TestCcfoliaBars(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	mythos := skills.Categories[SkillCategoryKnowledge].Skills["クトゥルフ神話"]
+	mythos.Single.Perm = 5
+	hp := 4
+	status.Parameters["HP"] = &hp
+
+	piece := Ccfolia("探索者", "", "", status, skills, nil)
+	if piece.Kind != "character" || piece.Data.Initiative != 11 {
+		t.Errorf("Expected a character piece with DEX initiative, got %+v", piece)
+	}
+	want := []CcfoliaStatus{{"HP", 4, 12}, {"MP", 11, 11}, {"SAN", 55, 94}}
+	for i, w := range want {
+		if piece.Data.Status[i] != w {
+			t.Errorf("Status[%d] = %+v, want %+v", i, piece.Data.Status[i], w)
+		}
+	}
+	if p := piece.Data.Params[len(piece.Data.Params)-1]; p.Label != "DB" || p.Value != "+0" {
+		t.Errorf("Expected DB param +0, got %+v", p)
+	}
+}
@@ -0,0 +1,45 @@
+package cthulhu6
+
+import (
+	"fmt"
+	"time"
+
+	"charaxiv/dice"
+)
+
+// MaxRollLog is the number of ability score rolls kept on a character
+const MaxRollLog = 50
+
+// StatRoll records a single ability score roll
+type StatRoll struct {
+	Key    string    `json:"key"`
+	Detail string    `json:"detail"` // rolled dice, e.g. "3D6[4,2,6]"
+	Value  int       `json:"value"`  // total after clamping to Min/Max
+	Time   time.Time `json:"time"`
+}
+
+// RollVariable rolls a variable's dice formula and clamps the total to its range
+func RollVariable(r *dice.Roller, key string, v Variable) (StatRoll, error) {
+	if v.Dice == "" {
+		return StatRoll{}, fmt.Errorf("variable %s has no dice formula", key)
+	}
+	res, err := r.Roll(v.Dice)
+	if err != nil {
+		return StatRoll{}, fmt.Errorf("roll %s: %w", key, err)
+	}
+	return StatRoll{
+		Key:    key,
+		Detail: res.Detail,
+		Value:  min(max(res.Total, v.Min), v.Max),
+		Time:   time.Now(),
+	}, nil
+}
+
+// AppendRolls appends rolls to the log, dropping the oldest beyond MaxRollLog
+func AppendRolls(log []StatRoll, rolls ...StatRoll) []StatRoll {
+	log = append(log, rolls...)
+	if len(log) > MaxRollLog {
+		log = log[len(log)-MaxRollLog:]
+	}
+	return log
+}
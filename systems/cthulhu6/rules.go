@@ -0,0 +1,254 @@
+package cthulhu6
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charaxiv/dice"
+)
+
+// RuleProfile selects the house rules used for derived values
+type RuleProfile string
+
+const (
+	RulesOfficial   RuleProfile = "official"    // rulebook values
+	RulesEduApp     RuleProfile = "edu-app"     // 職業P = EDU×10+APP×10
+	RulesHPFloor    RuleProfile = "hp-floor"    // HP rounds down
+	RulesDodgeFixed RuleProfile = "dodge-fixed" // 回避 starts at a fixed value
+	RulesDBExtended RuleProfile = "db-extended" // damage bonus continues past +1D6
+	RulesCustom     RuleProfile = "custom"      // formulas edited per character
+)
+
+// RuleProfiles lists the profiles in display order
+var RuleProfiles = []RuleProfile{RulesOfficial, RulesEduApp, RulesHPFloor, RulesDodgeFixed, RulesDBExtended, RulesCustom}
+
+// Label returns the display label for the profile
+func (p RuleProfile) Label() string {
+	switch p {
+	case RulesEduApp:
+		return "職業P=EDU×10+APP×10"
+	case RulesHPFloor:
+		return "HP切り捨て"
+	case RulesDodgeFixed:
+		return "回避固定(30)"
+	case RulesDBExtended:
+		return "DB拡張(+2D6以上)"
+	case RulesCustom:
+		return "カスタム"
+	default:
+		return "公式"
+	}
+}
+
+// ComputedOrder is the display order of computed values
+var ComputedOrder = []string{"初期SAN", "アイデア", "幸運", "知識", "職業P", "興味P"}
+
+// ParameterOrder is the display order of parameters
+var ParameterOrder = []string{"HP", "MP", "SAN"}
+
+// DamageBand maps STR+SIZ up to Max (inclusive) to a damage bonus
+type DamageBand struct {
+	Max   int    `json:"max"` // 0 for the final, unbounded band
+	Bonus string `json:"bonus"`
+}
+
+// RuleSet holds the formulas for every derived value. Formulas use the
+// characteristic keys and arithmetic accepted by EvalFormula.
+type RuleSet struct {
+	Computed    map[string]string `json:"computed"`    // keyed by ComputedOrder
+	Parameters  map[string]string `json:"parameters"`  // keyed by ParameterOrder
+	Skills      map[string]string `json:"skills"`      // initial value overrides by skill key
+	DamageBonus []DamageBand      `json:"damageBonus"` // ascending by Max
+}
+
+// officialRules are the rulebook formulas
+var officialRules = RuleSet{
+	Computed: map[string]string{
+		"初期SAN": "POW*5",
+		"アイデア":  "INT*5",
+		"幸運":    "POW*5",
+		"知識":    "EDU*5",
+		"職業P":   "EDU*20",
+		"興味P":   "INT*10",
+	},
+	Parameters: map[string]string{
+		"HP":  "(CON+SIZ+1)/2",
+		"MP":  "POW",
+		"SAN": "POW*5",
+	},
+	Skills: map[string]string{},
+	DamageBonus: []DamageBand{
+		{Max: 12, Bonus: "-1d6"},
+		{Max: 16, Bonus: "-1d4"},
+		{Max: 24, Bonus: "+0"},
+		{Max: 32, Bonus: "+1d4"},
+		{Bonus: "+1d6"},
+	},
+}
+
+// Clone returns a deep copy
+func (rs RuleSet) Clone() RuleSet {
+	return RuleSet{
+		Computed:    maps.Clone(rs.Computed),
+		Parameters:  maps.Clone(rs.Parameters),
+		Skills:      maps.Clone(rs.Skills),
+		DamageBonus: slices.Clone(rs.DamageBonus),
+	}
+}
+
+// ProfileRules returns the rule set of a built-in profile; custom and unknown
+// profiles return the official rules
+func ProfileRules(p RuleProfile) RuleSet {
+	rs := officialRules.Clone()
+	switch p {
+	case RulesEduApp:
+		rs.Computed["職業P"] = "EDU*10+APP*10"
+	case RulesHPFloor:
+		rs.Parameters["HP"] = "(CON+SIZ)/2"
+	case RulesDodgeFixed:
+		rs.Skills["回避"] = "30"
+	case RulesDBExtended:
+		last := len(rs.DamageBonus) - 1
+		rs.DamageBonus[last].Max = 40
+		rs.DamageBonus = append(rs.DamageBonus,
+			DamageBand{Max: 56, Bonus: "+2d6"},
+			DamageBand{Max: 72, Bonus: "+3d6"},
+			DamageBand{Bonus: "+4d6"},
+		)
+	}
+	return rs
+}
+
+// Rules is a character's selected house rules
+type Rules struct {
+	Profile RuleProfile `json:"profile"`
+	Custom  *RuleSet    `json:"custom,omitempty"` // formulas for the custom profile
+}
+
+// Set returns the rule set in effect
+func (r Rules) Set() RuleSet {
+	if r.Profile == RulesCustom && r.Custom != nil {
+		return *r.Custom
+	}
+	return ProfileRules(r.Profile)
+}
+
+// rules returns the rule set in effect for the status
+func (s *Status) rules() RuleSet {
+	return s.Rules.Set()
+}
+
+// formulaValue evaluates a rule formula; invalid formulas count as 0
+func (s *Status) formulaValue(formula string) int {
+	v, _ := EvalFormula(formula, s)
+	return v
+}
+
+// SetFormula validates and stores one custom formula. group is "computed",
+// "parameters" or "skills"; an empty skills formula removes the override.
+func (rs *RuleSet) SetFormula(group, key, formula string) error {
+	formula = strings.TrimSpace(formula)
+	var target map[string]string
+	switch group {
+	case "computed":
+		if !slices.Contains(ComputedOrder, key) {
+			return fmt.Errorf("unknown computed value %q", key)
+		}
+		target = rs.Computed
+	case "parameters":
+		if !slices.Contains(ParameterOrder, key) {
+			return fmt.Errorf("unknown parameter %q", key)
+		}
+		target = rs.Parameters
+	case "skills":
+		if !isEraSkill(key) {
+			return fmt.Errorf("unknown skill %q", key)
+		}
+		if formula == "" {
+			delete(rs.Skills, key)
+			return nil
+		}
+		if rs.Skills == nil {
+			rs.Skills = map[string]string{}
+		}
+		target = rs.Skills
+	default:
+		return fmt.Errorf("unknown rule group %q", group)
+	}
+	if _, err := EvalFormula(formula, NewStatus()); err != nil {
+		return err
+	}
+	target[key] = formula
+	return nil
+}
+
+// ParseDamageBands parses bands written as "12:-1d6, 16:-1d4, 24:+0, 32:+1d4, +1d6".
+// Every band but the last needs an ascending upper bound; the last has none.
+func ParseDamageBands(s string) ([]DamageBand, error) {
+	parts := strings.Split(strings.ReplaceAll(s, "、", ","), ",")
+	bands := make([]DamageBand, 0, len(parts))
+	prev := 0
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		band := DamageBand{Bonus: part}
+		if i < len(parts)-1 {
+			maxStr, bonus, ok := strings.Cut(part, ":")
+			if !ok {
+				return nil, fmt.Errorf("damage band %q must be <max>:<bonus>", part)
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(maxStr))
+			if err != nil || n <= prev {
+				return nil, fmt.Errorf("damage band %q must have an ascending max", part)
+			}
+			band = DamageBand{Max: n, Bonus: strings.TrimSpace(bonus)}
+			prev = n
+		}
+		if err := ValidateDamage(band.Bonus); err != nil {
+			return nil, err
+		}
+		bands = append(bands, band)
+	}
+	return bands, nil
+}
+
+// FormatDamageBands formats bands in the form accepted by ParseDamageBands
+func FormatDamageBands(bands []DamageBand) string {
+	parts := make([]string, len(bands))
+	for i, b := range bands {
+		if b.Max > 0 {
+			parts[i] = fmt.Sprintf("%d:%s", b.Max, b.Bonus)
+		} else {
+			parts[i] = b.Bonus
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SkillsDependOn reports whether any skill's initial value references the characteristic
+func (s *Status) SkillsDependOn(key string) bool {
+	overrides := s.rules().Skills
+	for _, d := range s.era().SkillDefs() {
+		formula := d.Initial
+		if f, ok := overrides[d.Key]; ok {
+			formula = f
+		}
+		if formulaReferences(formula, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// PointsDependOn reports whether 職業P or 興味P references the characteristic
+func (s *Status) PointsDependOn(key string) bool {
+	rs := s.rules()
+	return formulaReferences(rs.Computed["職業P"], key) || formulaReferences(rs.Computed["興味P"], key)
+}
+
+// formulaReferences reports whether a formula uses the characteristic
+func formulaReferences(formula, key string) bool {
+	return slices.Contains(dice.FormulaVariables(formula), key)
+}
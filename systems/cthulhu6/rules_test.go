@@ -0,0 +1,132 @@
+package cthulhu6
+
+import "testing"
+
+func TestRuleProfiles(t *testing.T) {
+	// STR 11, CON 11, SIZ 13, DEX 11, APP 11, EDU 14
+	tests := []struct {
+		profile RuleProfile
+		job, hp int
+		dodge   int
+	}{
+		{RulesOfficial, 280, 12, 22},
+		{RulesEduApp, 250, 12, 22},
+		{RulesHPFloor, 280, 12, 22},
+		{RulesDodgeFixed, 280, 12, 30},
+		{RulesCustom, 280, 12, 22}, // no custom set yet: official
+		{"", 280, 12, 22},
+	}
+	for _, tt := range tests {
+		s := NewStatus()
+		s.Rules = Rules{Profile: tt.profile}
+		if got := s.ComputedValues()["職業P"]; got != tt.job {
+			t.Errorf("%s: 職業P = %d, want %d", tt.profile, got, tt.job)
+		}
+		if got := s.DefaultParameters()["HP"]; got != tt.hp {
+			t.Errorf("%s: HP = %d, want %d", tt.profile, got, tt.hp)
+		}
+		if got := s.SkillInitialValue("回避"); got != tt.dodge {
+			t.Errorf("%s: 回避 = %d, want %d", tt.profile, got, tt.dodge)
+		}
+	}
+
+	// CON+SIZ odd: round up vs down
+	s := NewStatus()
+	con := s.Variables["CON"]
+	con.Base = 12
+	s.Variables["CON"] = con
+	if got := s.DefaultParameters()["HP"]; got != 13 {
+		t.Errorf("official HP = %d, want 13", got)
+	}
+	s.Rules.Profile = RulesHPFloor
+	if got := s.DefaultParameters()["HP"]; got != 12 {
+		t.Errorf("hp-floor HP = %d, want 12", got)
+	}
+}
+
+func TestDamageBonusBands(t *testing.T) {
+	tests := []struct {
+		profile  RuleProfile
+		str, siz int
+		want     string
+	}{
+		{RulesOfficial, 5, 7, "-1d6"},
+		{RulesOfficial, 5, 8, "-1d4"},
+		{RulesOfficial, 11, 13, "+0"},
+		{RulesOfficial, 12, 13, "+1d4"},
+		{RulesOfficial, 18, 18, "+1d6"},
+		{RulesOfficial, 30, 30, "+1d6"},
+		{RulesDBExtended, 18, 18, "+1d6"},
+		{RulesDBExtended, 25, 25, "+2d6"},
+		{RulesDBExtended, 40, 40, "+4d6"},
+	}
+	for _, tt := range tests {
+		s := NewStatus()
+		s.Rules.Profile = tt.profile
+		for key, v := range map[string]int{"STR": tt.str, "SIZ": tt.siz} {
+			vv := s.Variables[key]
+			vv.Base = v
+			s.Variables[key] = vv
+		}
+		if got := s.DamageBonus(); got != tt.want {
+			t.Errorf("%s STR %d SIZ %d: DamageBonus = %s, want %s", tt.profile, tt.str, tt.siz, got, tt.want)
+		}
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	rs := ProfileRules(RulesOfficial)
+	if err := rs.SetFormula("computed", "職業P", "EDU*15+INT*5"); err != nil {
+		t.Fatalf("SetFormula: %v", err)
+	}
+	if err := rs.SetFormula("skills", "回避", "DEX*3"); err != nil {
+		t.Fatalf("SetFormula: %v", err)
+	}
+	for _, bad := range [][3]string{
+		{"computed", "職業P", "LUK*5"},
+		{"computed", "幸運値", "POW*5"},
+		{"parameters", "HP", "1D6"},
+		{"skills", "料理", "10"},
+		{"tables", "x", "1"},
+	} {
+		if err := rs.SetFormula(bad[0], bad[1], bad[2]); err == nil {
+			t.Errorf("Expected %v to be rejected", bad)
+		}
+	}
+
+	s := NewStatus()
+	s.Rules = Rules{Profile: RulesCustom, Custom: &rs}
+	if got := s.ComputedValues()["職業P"]; got != 14*15+13*5 {
+		t.Errorf("custom 職業P = %d", got)
+	}
+	if got := s.SkillInitialValue("回避"); got != 33 {
+		t.Errorf("custom 回避 = %d, want 33", got)
+	}
+	if !s.SkillsDependOn("DEX") || !s.PointsDependOn("INT") || s.PointsDependOn("APP") {
+		t.Error("Unexpected custom dependencies")
+	}
+	// Clearing the override falls back to the definition
+	rs.SetFormula("skills", "回避", "")
+	if got := s.SkillInitialValue("回避"); got != 22 {
+		t.Errorf("cleared 回避 = %d, want 22", got)
+	}
+	// Profiles never share maps with the official rules
+	if ProfileRules(RulesOfficial).Computed["職業P"] != "EDU*20" {
+		t.Error("Official rules were modified")
+	}
+}
+
+func TestParseDamageBands(t *testing.T) {
+	bands, err := ParseDamageBands("12:-1d6, 16:-1d4, 24:+0, 32:+1d4, +1d6")
+	if err != nil {
+		t.Fatalf("ParseDamageBands: %v", err)
+	}
+	if got := FormatDamageBands(bands); got != FormatDamageBands(officialRules.DamageBonus) {
+		t.Errorf("Round trip = %q", got)
+	}
+	for _, bad := range []string{"", "12-1d6, +0", "16:+0, 12:+1d4, +1d6", "12:abc, +0", "12:+0, 20:+1d4"} {
+		if _, err := ParseDamageBands(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
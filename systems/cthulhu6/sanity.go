@@ -0,0 +1,176 @@
+package cthulhu6
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charaxiv/dice"
+)
+
+// InsanityKind classifies a recorded insanity
+type InsanityKind string
+
+const (
+	InsanityTemporary  InsanityKind = "temporary"  // 一時的狂気
+	InsanityIndefinite InsanityKind = "indefinite" // 不定の狂気
+	InsanityPermanent  InsanityKind = "permanent"  // 永久的狂気
+	InsanityPhobia     InsanityKind = "phobia"     // 恐怖症
+	InsanityMania      InsanityKind = "mania"      // 偏執症
+)
+
+// InsanityKinds lists the kinds in display order
+var InsanityKinds = []InsanityKind{InsanityTemporary, InsanityIndefinite, InsanityPermanent, InsanityPhobia, InsanityMania}
+
+// Label returns the display label for the kind
+func (k InsanityKind) Label() string {
+	switch k {
+	case InsanityTemporary:
+		return "一時的狂気"
+	case InsanityIndefinite:
+		return "不定の狂気"
+	case InsanityPermanent:
+		return "永久的狂気"
+	case InsanityPhobia:
+		return "恐怖症"
+	case InsanityMania:
+		return "偏執症"
+	default:
+		return string(k)
+	}
+}
+
+// Valid reports whether k is a known kind
+func (k InsanityKind) Valid() bool {
+	for _, known := range InsanityKinds {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Insanity is a recorded insanity, phobia or mania
+type Insanity struct {
+	Label string       `json:"label"` // e.g. "閉所恐怖症"
+	Kind  InsanityKind `json:"kind"`
+	Time  time.Time    `json:"time"`
+}
+
+// TemporaryInsanityLoss is the SAN lost at once that triggers temporary insanity
+const TemporaryInsanityLoss = 5
+
+// MaxSanCheckLog is the number of SAN checks kept on a character
+const MaxSanCheckLog = 50
+
+// SanCheck records a single SAN check
+type SanCheck struct {
+	Expr       string    `json:"expr"` // loss expression, e.g. "1/1D6"
+	SanBefore  int       `json:"sanBefore"`
+	Roll       int       `json:"roll"` // 1D100
+	Success    bool      `json:"success"`
+	LossDetail string    `json:"lossDetail"` // rolled loss, e.g. "1D6[4]"
+	Loss       int       `json:"loss"`
+	Temporary  bool      `json:"temporary"`  // this check triggered temporary insanity
+	Indefinite bool      `json:"indefinite"` // this check pushed the session loss over the threshold
+	Time       time.Time `json:"time"`
+}
+
+// SanAfter returns SAN after the loss
+func (c SanCheck) SanAfter() int {
+	return max(0, c.SanBefore-c.Loss)
+}
+
+// Sanity tracks SAN loss and insanity for a character
+type Sanity struct {
+	SessionStart *int       `json:"sessionStart"` // SAN at the first check of the session; nil when no session
+	SessionLoss  int        `json:"sessionLoss"`  // SAN lost since the session started
+	Temporary    bool       `json:"temporary"`    // currently temporarily insane
+	Indefinite   bool       `json:"indefinite"`   // currently indefinitely insane
+	Insanities   []Insanity `json:"insanities"`
+	Checks       []SanCheck `json:"checks"` // oldest first
+}
+
+// IndefiniteReached reports whether the session loss is at least 1/5 of the SAN the session started with
+func (s Sanity) IndefiniteReached() bool {
+	return s.SessionStart != nil && *s.SessionStart > 0 && s.SessionLoss*5 >= *s.SessionStart
+}
+
+// ParseSanLoss splits a loss expression like "1/1D6" or "0/1D3" into success and failure parts
+func ParseSanLoss(expr string) (success, failure string, err error) {
+	expr = strings.ReplaceAll(strings.TrimSpace(expr), "／", "/")
+	parts := strings.Split(expr, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("SAN loss %q must be <success>/<failure>", expr)
+	}
+	success, failure = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	for _, p := range []string{success, failure} {
+		e, err := dice.Parse(p)
+		if err != nil {
+			return "", "", fmt.Errorf("SAN loss %q: %w", expr, err)
+		}
+		if e.HasTarget() {
+			return "", "", fmt.Errorf("SAN loss %q must not contain a comparison", expr)
+		}
+	}
+	return success, failure, nil
+}
+
+// RollSanCheck rolls 1D100 against san, rolls the matching loss and updates the sanity state
+func RollSanCheck(r *dice.Roller, expr string, san int, sanity *Sanity) (SanCheck, error) {
+	successExpr, failureExpr, err := ParseSanLoss(expr)
+	if err != nil {
+		return SanCheck{}, err
+	}
+
+	roll, err := r.Roll("1D100")
+	if err != nil {
+		return SanCheck{}, err
+	}
+	check := SanCheck{
+		Expr:      successExpr + "/" + failureExpr,
+		SanBefore: san,
+		Roll:      roll.Total,
+		Success:   roll.Total <= san,
+		Time:      time.Now(),
+	}
+
+	lossExpr := failureExpr
+	if check.Success {
+		lossExpr = successExpr
+	}
+	loss, err := r.Roll(lossExpr)
+	if err != nil {
+		return SanCheck{}, err
+	}
+	check.LossDetail = loss.Detail
+	check.Loss = min(max(loss.Total, 0), san)
+
+	if sanity.SessionStart == nil {
+		start := san
+		sanity.SessionStart = &start
+	}
+	wasIndefinite := sanity.IndefiniteReached()
+	sanity.SessionLoss += check.Loss
+
+	check.Temporary = check.Loss >= TemporaryInsanityLoss
+	check.Indefinite = !wasIndefinite && sanity.IndefiniteReached()
+	if check.Temporary {
+		sanity.Temporary = true
+	}
+	if check.Indefinite {
+		sanity.Indefinite = true
+	}
+
+	sanity.Checks = append(sanity.Checks, check)
+	if len(sanity.Checks) > MaxSanCheckLog {
+		sanity.Checks = sanity.Checks[len(sanity.Checks)-MaxSanCheckLog:]
+	}
+	return check, nil
+}
+
+// ResetSession starts a new session, clearing the tracked loss
+func (s *Sanity) ResetSession() {
+	s.SessionStart = nil
+	s.SessionLoss = 0
+}
@@ -0,0 +1,120 @@
+package cthulhu6
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"charaxiv/pdf"
+)
+
+// SheetMemo is a titled memo printed at the end of the PDF sheet
+type SheetMemo struct {
+	Title string
+	Text  string
+}
+
+// signed formats a modifier, leaving zero blank so changes stand out
+func signed(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+d", n)
+}
+
+// blank formats a point allocation, leaving zero blank
+func blank(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// WriteSheetPDF writes a printable A4 character sheet: characteristics,
+// computed values, HP/MP/SAN, every skill with its point breakdown and the
+// given memos. Empty memos are left out; callers choose which memos to pass,
+// so secret memos are only printed when the caller includes them.
+func WriteSheetPDF(w io.Writer, name string, status *Status, skills *Skills, memos []SheetMemo) error {
+	doc, err := pdf.New(name)
+	if err != nil {
+		return err
+	}
+	doc.Title(name)
+	if e, ok := EraByID(status.Era); ok {
+		doc.Text(fmt.Sprintf("時代: %s　ルール: %s", e.Name, status.Rules.Profile.Label()))
+	}
+
+	doc.Heading("能力値")
+	var rows [][]string
+	for _, key := range VariableOrder {
+		v := status.Variables[key]
+		rows = append(rows, []string{key, strconv.Itoa(v.Base), signed(v.Perm), signed(v.Temp), strconv.Itoa(v.Sum()), strconv.Itoa(v.Sum() * 5)})
+	}
+	doc.Table([]pdf.Column{
+		{Label: "能力値", Width: 30},
+		{Label: "基本", Width: 20, Right: true},
+		{Label: "変動", Width: 20, Right: true},
+		{Label: "一時", Width: 20, Right: true},
+		{Label: "合計", Width: 20, Right: true},
+		{Label: "×5", Width: 20, Right: true},
+	}, rows)
+
+	doc.Heading("算出値")
+	computed := status.ComputedValues()
+	rows = nil
+	for _, key := range ComputedOrder {
+		rows = append(rows, []string{key, strconv.Itoa(computed[key])})
+	}
+	rows = append(rows, []string{"ダメージボーナス", status.DamageBonus()})
+	doc.Table([]pdf.Column{
+		{Label: "項目", Width: 40},
+		{Label: "値", Width: 25, Right: true},
+	}, rows)
+
+	doc.Heading("HP / MP / SAN")
+	maxima := status.DefaultParameters()
+	maxima["SAN"] = status.MaxSanity(skills)
+	rows = nil
+	for _, key := range ParameterOrder {
+		rows = append(rows, []string{key, strconv.Itoa(status.EffectiveParameter(key)), strconv.Itoa(maxima[key])})
+	}
+	rows = append(rows, []string{"不定の狂気", strconv.Itoa(status.Indefinite()), ""})
+	doc.Table([]pdf.Column{
+		{Label: "項目", Width: 40},
+		{Label: "現在", Width: 25, Right: true},
+		{Label: "最大", Width: 25, Right: true},
+	}, rows)
+
+	doc.Heading("技能")
+	rows = nil
+	for _, row := range status.SkillRows(skills) {
+		category := string(row.Category)
+		if category == "" {
+			category = "独自技能"
+		}
+		rows = append(rows, []string{category, row.Label, strconv.Itoa(row.Init), blank(row.Job), blank(row.Hobby), signed(row.Perm), signed(row.Temp), strconv.Itoa(row.Total())})
+	}
+	doc.Table([]pdf.Column{
+		{Label: "分類", Width: 24},
+		{Label: "技能", Width: 58},
+		{Label: "初期値", Width: 16, Right: true},
+		{Label: "職業P", Width: 16, Right: true},
+		{Label: "興味P", Width: 16, Right: true},
+		{Label: "成長", Width: 16, Right: true},
+		{Label: "一時", Width: 16, Right: true},
+		{Label: "合計", Width: 18, Right: true},
+	}, rows)
+
+	for _, m := range memos {
+		if m.Text == "" {
+			continue
+		}
+		doc.Heading(m.Title)
+		doc.Text(m.Text)
+	}
+
+	if _, err := doc.WriteTo(w); err != nil {
+		return fmt.Errorf("write PDF: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cthulhu6
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteSheetPDF(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	skills.Custom = append(skills.Custom, CustomSkill{Name: "料理", Perm: 40})
+	memos := []SheetMemo{{Title: "公開メモ", Text: "一行目\n二行目"}, {Title: "秘匿メモ"}}
+
+	var buf bytes.Buffer
+	if err := WriteSheetPDF(&buf, "探索者", status, skills, memos); err != nil {
+		t.Fatalf("WriteSheetPDF: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Error("Expected a PDF header")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("/MediaBox [ 0 0 595.00 842.00 ]")) {
+		t.Error("Expected A4 pages")
+	}
+}
+
+func TestSkillRowsBreakDownPoints(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	spot := skills.Categories[SkillCategoryInvestigation].Skills["目星"]
+	spot.Single.Job, spot.Single.Temp = 30, -5
+	drive := skills.Categories[SkillCategoryAction].Skills["運転"]
+	drive.Multi.Genres = append(drive.Multi.Genres, SkillGenre{Label: "自動車", Hobby: 20})
+
+	var found int
+	for _, row := range status.SkillRows(skills) {
+		switch row.Label {
+		case "目星":
+			found++
+			if row.Init != 25 || row.Job != 30 || row.Temp != -5 || row.Total() != 50 {
+				t.Errorf("Expected 目星 25+30-5=50, got %+v", row)
+			}
+		case "運転(自動車)":
+			found++
+			if row.Category != SkillCategoryAction || row.Hobby != 20 || row.Total() != 40 {
+				t.Errorf("Expected 運転(自動車) 20+20=40, got %+v", row)
+			}
+		}
+	}
+	if found != 2 {
+		t.Errorf("Expected both skills, found %d", found)
+	}
+}
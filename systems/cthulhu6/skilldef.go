@@ -0,0 +1,89 @@
+package cthulhu6
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"charaxiv/dice"
+)
+
+//go:embed skills.json
+var skillsJSON []byte
+
+// SkillDef defines a built-in skill
+type SkillDef struct {
+	Key       string        `json:"key"`
+	Category  SkillCategory `json:"category"`
+	Order     int           `json:"order"`               // display order within the category
+	Multi     bool          `json:"multi,omitempty"`     // has genres, e.g. 運転, 芸術
+	Initial   string        `json:"initial"`             // initial value formula, e.g. "25", "DEX*2"
+	Essential bool          `json:"essential,omitempty"` // shown in bold
+	Genres    []string      `json:"genres,omitempty"`    // genres a new sheet starts with
+}
+
+// SkillDefs is the built-in skill definitions in file order
+var SkillDefs = mustLoadSkillDefs()
+
+var skillDefsByKey = func() map[string]SkillDef {
+	m := make(map[string]SkillDef, len(SkillDefs))
+	for _, d := range SkillDefs {
+		m[d.Key] = d
+	}
+	return m
+}()
+
+func mustLoadSkillDefs() []SkillDef {
+	var defs []SkillDef
+	if err := json.Unmarshal(skillsJSON, &defs); err != nil {
+		panic(fmt.Sprintf("cthulhu6: parse skills.json: %v", err))
+	}
+	if err := validateSkillDefs(defs); err != nil {
+		panic(fmt.Sprintf("cthulhu6: skills.json: %v", err))
+	}
+	return defs
+}
+
+// validateSkillDefs checks keys, categories, orders and formulas
+func validateSkillDefs(defs []SkillDef) error {
+	keys := map[string]bool{}
+	orders := map[SkillCategory][]int{}
+	status := NewStatus()
+	for _, d := range defs {
+		if d.Key == "" || keys[d.Key] {
+			return fmt.Errorf("empty or duplicate key %q", d.Key)
+		}
+		keys[d.Key] = true
+		if !slices.Contains(CategoryOrder, d.Category) {
+			return fmt.Errorf("%s: unknown category %q", d.Key, d.Category)
+		}
+		if slices.Contains(orders[d.Category], d.Order) {
+			return fmt.Errorf("%s: duplicate order %d in %s", d.Key, d.Order, d.Category)
+		}
+		orders[d.Category] = append(orders[d.Category], d.Order)
+		if len(d.Genres) > 0 && !d.Multi {
+			return fmt.Errorf("%s: genres on a single skill", d.Key)
+		}
+		if _, err := EvalFormula(d.Initial, status); err != nil {
+			return fmt.Errorf("%s: %w", d.Key, err)
+		}
+	}
+	return nil
+}
+
+// SkillDefByKey returns the definition of a built-in skill
+func SkillDefByKey(key string) (SkillDef, bool) {
+	d, ok := skillDefsByKey[key]
+	return d, ok
+}
+
+// EvalFormula evaluates an initial value formula such as "DEX*2" against the
+// characteristic totals. Formulas may use integers, characteristic keys and
+// arithmetic, but no dice.
+func EvalFormula(formula string, status *Status) (int, error) {
+	return dice.EvalFormula(formula, func(key string) (int, bool) {
+		v, ok := status.Variables[key]
+		return v.Sum(), ok
+	})
+}
@@ -0,0 +1,93 @@
+package cthulhu6
+
+import "testing"
+
+func TestSkillDefsInitialValues(t *testing.T) {
+	status := NewStatus()
+	dex := status.Variables["DEX"]
+	dex.Base = 14
+	status.Variables["DEX"] = dex
+	edu := status.Variables["EDU"]
+	edu.Perm = 2
+	status.Variables["EDU"] = edu
+
+	want := map[string]int{
+		"回避": 28, "母国語": 80, "こぶし": 50, "登攀": 40, "応急手当": 30, "ショットガン": 30,
+		"キック": 25, "目星": 25, "運転": 20, "歴史": 20, "信用": 15, "隠す": 15,
+		"頭突き": 10, "経理": 10, "芸術": 5, "医学": 5, "変装": 1, "地質学": 1,
+		"クトゥルフ神話": 0, "料理": 1, // custom skills start at 1
+	}
+	for key, v := range want {
+		if got := status.SkillInitialValue(key); got != v {
+			t.Errorf("SkillInitialValue(%s) = %d, want %d", key, got, v)
+		}
+	}
+}
+
+func TestNewSkillsFromDefs(t *testing.T) {
+	skills := NewSkills()
+	n := 0
+	for i, cat := range CategoryOrder {
+		data := skills.Categories[cat]
+		if data.Order != i {
+			t.Errorf("%s: order %d, want %d", cat, data.Order, i)
+		}
+		n += len(data.Skills)
+	}
+	if n != len(SkillDefs) {
+		t.Errorf("Expected %d skills, got %d", len(SkillDefs), n)
+	}
+
+	native := skills.Categories[SkillCategoryKnowledge].Skills["母国語"]
+	if !native.IsMulti() || len(native.Multi.Genres) != 1 {
+		t.Errorf("Expected 母国語 to start with one genre, got %+v", native)
+	}
+	if drive := skills.Categories[SkillCategoryAction].Skills["運転"]; !drive.IsMulti() || len(drive.Multi.Genres) != 0 {
+		t.Errorf("Expected 運転 to start with no genres, got %+v", drive)
+	}
+	if !IsEssentialSkill("図書館") || IsEssentialSkill("ライフル") {
+		t.Error("Unexpected essential flags")
+	}
+}
+
+func TestEvalFormula(t *testing.T) {
+	status := NewStatus() // DEX 11, EDU 14
+	tests := []struct {
+		formula string
+		want    int
+		err     bool
+	}{
+		{"25", 25, false},
+		{"DEX*2", 22, false},
+		{"EDU×5", 70, false},
+		{"(DEX+EDU)/5", 5, false},
+		{"LUK*5", 0, true},
+		{"1D6", 0, true},
+		{"DEX>=3", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := EvalFormula(tt.formula, status)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("EvalFormula(%q) = %d, %v; want %d, err=%v", tt.formula, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestValidateSkillDefs(t *testing.T) {
+	if err := validateSkillDefs(SkillDefs); err != nil {
+		t.Fatalf("skills.json: %v", err)
+	}
+	bad := [][]SkillDef{
+		{{Key: "a", Category: SkillCategoryCombat, Initial: "1"}, {Key: "a", Category: SkillCategoryCombat, Order: 1, Initial: "1"}},
+		{{Key: "a", Category: "魔法技能", Initial: "1"}},
+		{{Key: "a", Category: SkillCategoryCombat, Initial: "1"}, {Key: "b", Category: SkillCategoryCombat, Initial: "1"}},
+		{{Key: "a", Category: SkillCategoryCombat, Initial: "1", Genres: []string{"x"}}},
+		{{Key: "a", Category: SkillCategoryCombat, Initial: "STR+"}},
+	}
+	for i, defs := range bad {
+		if err := validateSkillDefs(defs); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package cthulhu6
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// udonariumData is a <data> node of an Udonarium character
+type udonariumData struct {
+	XMLName      xml.Name        `xml:"data"`
+	Type         string          `xml:"type,attr,omitempty"`
+	Name         string          `xml:"name,attr"`
+	CurrentValue string          `xml:"currentValue,attr,omitempty"`
+	Value        string          `xml:",chardata"`
+	Children     []udonariumData `xml:"data"`
+}
+
+// udonariumCharacter is the root of an Udonarium character piece
+type udonariumCharacter struct {
+	XMLName      xml.Name      `xml:"character"`
+	LocationName string        `xml:"location.name,attr"`
+	LocationX    int           `xml:"location.x,attr"`
+	LocationY    int           `xml:"location.y,attr"`
+	PosZ         int           `xml:"posZ,attr"`
+	Rotate       int           `xml:"rotate,attr"`
+	Roll         int           `xml:"roll,attr"`
+	Data         udonariumData `xml:"data"`
+	ChatPalette  struct {
+		Dicebot string `xml:"dicebot,attr"`
+		Text    string `xml:",chardata"`
+	} `xml:"chat-palette"`
+}
+
+// udonariumGroup returns a named <data> node holding children
+func udonariumGroup(name string, children ...udonariumData) udonariumData {
+	return udonariumData{Name: name, Children: children}
+}
+
+// udonariumValue returns a named <data> node holding a value
+func udonariumValue(name, v string) udonariumData {
+	return udonariumData{Name: name, Value: v}
+}
+
+// UdonariumXML returns the character as an Udonarium character piece:
+// HP/MP/SAN as resources, characteristics, every skill total, the memo as a
+// note and the BCDice chat palette. imageID is the portrait's identifier, or
+// empty for none.
+func UdonariumXML(name, memo, imageID string, status *Status, skills *Skills, weapons []Weapon) ([]byte, error) {
+	maxima := status.DefaultParameters()
+	maxima["SAN"] = status.MaxSanity(skills)
+	resources := udonariumGroup("リソース")
+	for _, key := range ParameterOrder {
+		resources.Children = append(resources.Children, udonariumData{
+			Type:         "numberResource",
+			Name:         key,
+			CurrentValue: strconv.Itoa(status.EffectiveParameter(key)),
+			Value:        strconv.Itoa(maxima[key]),
+		})
+	}
+
+	abilities := udonariumGroup("能力値")
+	for _, key := range VariableOrder {
+		abilities.Children = append(abilities.Children, udonariumValue(key, strconv.Itoa(status.Variables[key].Sum())))
+	}
+	abilities.Children = append(abilities.Children, udonariumValue("DB", status.DamageBonus()))
+
+	skillList := udonariumGroup("技能")
+	for _, sk := range status.PaletteSkills(skills) {
+		skillList.Children = append(skillList.Children, udonariumValue(sk.Label, strconv.Itoa(sk.Value)))
+	}
+
+	c := udonariumCharacter{LocationName: "table"}
+	c.Data = udonariumGroup("character",
+		udonariumGroup("image", udonariumData{Type: "image", Name: "imageIdentifier", Value: imageID}),
+		udonariumGroup("common", udonariumValue("name", name), udonariumValue("size", "1")),
+		udonariumGroup("detail", resources, abilities, skillList,
+			udonariumGroup("情報", udonariumData{Type: "note", Name: "説明", Value: memo})),
+	)
+	c.ChatPalette.Dicebot = "Cthulhu"
+	c.ChatPalette.Text = ChatPalette(status, skills, weapons)
+
+	out, err := xml.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal Udonarium character: %w", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// imageExtensions maps detected image types to file extensions
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ImageExtension returns the file extension of an image Udonarium can load,
+// or false when image is not one
+func ImageExtension(image []byte) (string, bool) {
+	ext, ok := imageExtensions[http.DetectContentType(image)]
+	return ext, ok
+}
+
+// WriteUdonariumZip writes a zip Udonarium can load: the character XML and,
+// when image is non-empty, the portrait named by its SHA-256 identifier as
+// Udonarium expects
+func WriteUdonariumZip(w io.Writer, name, memo string, image []byte, status *Status, skills *Skills, weapons []Weapon) error {
+	var imageID, imageFile string
+	if len(image) > 0 {
+		ext, ok := ImageExtension(image)
+		if !ok {
+			return errors.New("portrait is not a supported image type")
+		}
+		sum := sha256.Sum256(image)
+		imageID = hex.EncodeToString(sum[:])
+		imageFile = imageID + ext
+	}
+
+	data, err := UdonariumXML(name, memo, imageID, status, skills, weapons)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeZipFile(zw, "data.xml", data); err != nil {
+		return err
+	}
+	if imageFile != "" {
+		if err := writeZipFile(zw, imageFile, image); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeZipFile adds one file to a zip
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", name, err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cthulhu6
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUdonariumXML(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	hp := 3
+	status.Parameters["HP"] = &hp
+
+	data, err := UdonariumXML("探索者", "メモ", "", status, skills, nil)
+	if err != nil {
+		t.Fatalf("UdonariumXML: %v", err)
+	}
+	xml := string(data)
+	for _, want := range []string{
+		`<data type="numberResource" name="HP" currentValue="3">`,
+		`<data name="STR">`,
+		`<data name="目星">25</data>`,
+		`<data type="note" name="説明">メモ</data>`,
+		`<chat-palette dicebot="Cthulhu">`,
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("Expected XML to contain %q", want)
+		}
+	}
+}
+
+func TestWriteUdonariumZipRejectsUnknownImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteUdonariumZip(&buf, "探索者", "", []byte("not an image"), NewStatus(), NewSkills(), nil); err == nil {
+		t.Error("Expected error for a non-image portrait")
+	}
+
+	buf.Reset()
+	if err := WriteUdonariumZip(&buf, "探索者", "", nil, NewStatus(), NewSkills(), nil); err != nil {
+		t.Fatalf("WriteUdonariumZip: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Read zip: %v", err)
+	}
+	if len(zr.File) != 1 || zr.File[0].Name != "data.xml" {
+		t.Errorf("Expected only data.xml without a portrait, got %d files", len(zr.File))
+	}
+}
@@ -0,0 +1,226 @@
+package cthulhu6
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SkillCap is the highest total a skill may reach through point allocation
+const SkillCap = 99
+
+// MythosSkill is the skill that cannot receive job or hobby points
+const MythosSkill = "クトゥルフ神話"
+
+// WarningKind classifies a validation warning
+type WarningKind string
+
+const (
+	WarningJobOverspent   WarningKind = "job-overspent"   // more 職業P spent than available
+	WarningHobbyOverspent WarningKind = "hobby-overspent" // more 興味P spent than available
+	WarningSkillOverCap   WarningKind = "skill-over-cap"  // skill total above SkillCap
+	WarningMythosPoints   WarningKind = "mythos-points"   // クトゥルフ神話 given job/hobby points
+	WarningVariableRange  WarningKind = "variable-range"  // characteristic base outside Min/Max
+	WarningGeneration     WarningKind = "generation"      // bases break the point-buy or array rules
+	WarningNotOccupation  WarningKind = "not-occupation"  // 職業P spent outside the occupation skills
+	WarningOccupationPick WarningKind = "occupation-pick" // "choose N" slot not filled
+	WarningCreditRange    WarningKind = "credit-range"    // 信用 outside the occupation's range
+)
+
+// Warning is a single rule violation found on a sheet
+type Warning struct {
+	Kind    WarningKind
+	Subject string // skill label or variable key; empty for sheet-wide warnings
+	Message string
+}
+
+// ValidateSheet returns warnings for skill point allocation and characteristic rules,
+// ordered characteristics first, then points, then skills in display order.
+func ValidateSheet(status *Status, skills *Skills) []Warning {
+	var warnings []Warning
+
+	for _, key := range VariableOrder {
+		v := status.Variables[key]
+		if v.Base < v.Min || v.Base > v.Max {
+			warnings = append(warnings, Warning{
+				Kind:    WarningVariableRange,
+				Subject: key,
+				Message: fmt.Sprintf("%sの基本値%dが範囲外です(%d〜%d)", key, v.Base, v.Min, v.Max),
+			})
+		}
+	}
+	if err := status.validateGeneration(); err != nil {
+		msg := "能力値が作成ルールに合っていません"
+		switch {
+		case errors.Is(err, ErrOverBudget):
+			msg = fmt.Sprintf("能力値の合計%dがポイント%dを超えています", status.PointsUsed(), status.Generation.Budget)
+		case errors.Is(err, ErrNotInArray):
+			msg = "能力値が固定値の組み合わせと一致しません"
+		}
+		warnings = append(warnings, Warning{Kind: WarningGeneration, Message: msg})
+	}
+
+	job, hobby := status.RemainingPoints(skills)
+	if job < 0 {
+		warnings = append(warnings, Warning{
+			Kind:    WarningJobOverspent,
+			Message: fmt.Sprintf("職業Pを%d超過しています", -job),
+		})
+	}
+	if hobby < 0 {
+		warnings = append(warnings, Warning{
+			Kind:    WarningHobbyOverspent,
+			Message: fmt.Sprintf("興味Pを%d超過しています", -hobby),
+		})
+	}
+
+	for _, cat := range CategoryOrder {
+		catData := skills.Categories[cat]
+		for _, key := range sortedSkillKeys(catData) {
+			skill := catData.Skills[key]
+			init := status.SkillInitialValue(key)
+			switch {
+			case skill.IsSingle():
+				warnings = appendSkillWarnings(warnings, key, key, init+skill.Single.Sum(), skill.Single.Job, skill.Single.Hobby)
+			case skill.IsMulti():
+				for _, g := range skill.Multi.Genres {
+					label := key
+					if g.Label != "" {
+						label = fmt.Sprintf("%s(%s)", key, g.Label)
+					}
+					warnings = appendSkillWarnings(warnings, key, label, init+g.Sum(), g.Job, g.Hobby)
+				}
+			}
+		}
+	}
+
+	for _, cs := range skills.Custom {
+		label := cs.Name
+		if label == "" {
+			label = "無名"
+		}
+		warnings = appendSkillWarnings(warnings, cs.Name, label, cs.Total(), cs.Job, cs.Hobby)
+	}
+
+	if o, ok := OccupationByID(skills.Occupation.ID); ok {
+		warnings = appendOccupationWarnings(warnings, o, status, skills)
+	}
+
+	return warnings
+}
+
+// sortedSkillKeys returns the skill keys of a category in display order
+func sortedSkillKeys(catData SkillCategoryData) []string {
+	keys := make([]string, 0, len(catData.Skills))
+	for key := range catData.Skills {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return catData.Skills[keys[i]].Order < catData.Skills[keys[j]].Order
+	})
+	return keys
+}
+
+// appendOccupationWarnings checks 職業P against the occupation skills, the
+// "choose N" slots and the 信用 range. A plain entry on a multi skill allows
+// every genre; an AnyGenre entry allows one genre.
+func appendOccupationWarnings(warnings []Warning, o Occupation, status *Status, skills *Skills) []Warning {
+	sel := skills.Occupation.Normalize(o)
+	plain := map[string]bool{}
+	exact := map[string]bool{}
+	anySlots := map[string]int{}
+	for _, entry := range o.Allowed(sel) {
+		switch {
+		case entry.Genre != "":
+			exact[entry.Label()] = true
+		case entry.AnyGenre:
+			anySlots[entry.Key]++
+		default:
+			plain[entry.Key] = true
+		}
+	}
+
+	notOccupation := func(label string) Warning {
+		return Warning{
+			Kind:    WarningNotOccupation,
+			Subject: label,
+			Message: fmt.Sprintf("%sは%sの職業技能ではありません", label, o.Name),
+		}
+	}
+
+	for _, cat := range CategoryOrder {
+		catData := skills.Categories[cat]
+		for _, key := range sortedSkillKeys(catData) {
+			skill := catData.Skills[key]
+			switch {
+			case skill.IsSingle():
+				if skill.Single.Job > 0 && !plain[key] {
+					warnings = append(warnings, notOccupation(key))
+				}
+			case skill.IsMulti():
+				for _, g := range skill.Multi.Genres {
+					if g.Job <= 0 || plain[key] {
+						continue
+					}
+					label := key
+					if g.Label != "" {
+						label = fmt.Sprintf("%s(%s)", key, g.Label)
+					}
+					switch {
+					case exact[label]:
+					case anySlots[key] > 0:
+						anySlots[key]--
+					default:
+						warnings = append(warnings, notOccupation(label))
+					}
+				}
+			}
+		}
+	}
+	for _, cs := range skills.Custom {
+		if cs.Job > 0 && !plain[cs.Name] {
+			label := cs.Name
+			if label == "" {
+				label = "無名"
+			}
+			warnings = append(warnings, notOccupation(label))
+		}
+	}
+
+	for i, choice := range o.Choices {
+		if left := choice.Count - len(sel.Choices[i]); left > 0 {
+			warnings = append(warnings, Warning{
+				Kind:    WarningOccupationPick,
+				Message: fmt.Sprintf("%sの選択技能をあと%d個選んでください", o.Name, left),
+			})
+		}
+	}
+
+	if credit, ok := status.SkillValue(skills, "信用"); ok && (credit < o.Credit[0] || credit > o.Credit[1]) {
+		warnings = append(warnings, Warning{
+			Kind:    WarningCreditRange,
+			Subject: "信用",
+			Message: fmt.Sprintf("信用%dが%sの範囲外です(%d〜%d)", credit, o.Name, o.Credit[0], o.Credit[1]),
+		})
+	}
+	return warnings
+}
+
+// appendSkillWarnings checks one skill or genre for the cap and mythos rules
+func appendSkillWarnings(warnings []Warning, key, label string, total, job, hobby int) []Warning {
+	if total > SkillCap {
+		warnings = append(warnings, Warning{
+			Kind:    WarningSkillOverCap,
+			Subject: label,
+			Message: fmt.Sprintf("%sが%dを超えています(%d)", label, SkillCap, total),
+		})
+	}
+	if key == MythosSkill && (job != 0 || hobby != 0) {
+		warnings = append(warnings, Warning{
+			Kind:    WarningMythosPoints,
+			Subject: label,
+			Message: fmt.Sprintf("%sに職業P・興味Pは割り振れません", label),
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,63 @@
+package cthulhu6
+
+import "testing"
+
+func TestValidateSheet(t *testing.T) {
+	kinds := func(ws []Warning) map[WarningKind]int {
+		m := map[WarningKind]int{}
+		for _, w := range ws {
+			m[w.Kind]++
+		}
+		return m
+	}
+
+	status, skills := NewStatus(), NewSkills()
+	if ws := ValidateSheet(status, skills); len(ws) != 0 {
+		t.Fatalf("Expected no warnings for a new sheet, got %+v", ws)
+	}
+
+	str := status.Variables["STR"]
+	str.Base = str.Max + 1
+	status.Variables["STR"] = str
+
+	job, hobby := status.RemainingPoints(skills)
+	combat := skills.Categories[SkillCategoryCombat]
+	kick := combat.Skills["キック"]
+	kick.Single.Job = job + 10
+	kick.Single.Perm = 80
+	combat.Skills["キック"] = kick
+
+	knowledge := skills.Categories[SkillCategoryKnowledge]
+	mythos := knowledge.Skills[MythosSkill]
+	mythos.Single.Hobby = 1
+	knowledge.Skills[MythosSkill] = mythos
+
+	skills.Custom = append(skills.Custom, CustomSkill{Name: "料理", Hobby: hobby + 99})
+
+	got := kinds(ValidateSheet(status, skills))
+	want := map[WarningKind]int{
+		WarningVariableRange:  1,
+		WarningJobOverspent:   1,
+		WarningHobbyOverspent: 1,
+		WarningSkillOverCap:   2, // キック and 料理
+		WarningMythosPoints:   1,
+	}
+	for kind, n := range want {
+		if got[kind] != n {
+			t.Errorf("Expected %d %s warnings, got %d", n, kind, got[kind])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Unexpected warning kinds %v", got)
+	}
+}
+
+func TestValidateSheetGeneration(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	status.Generation = Generation{Mode: GenerationPointBuy, Budget: 10}
+
+	ws := ValidateSheet(status, skills)
+	if len(ws) != 1 || ws[0].Kind != WarningGeneration {
+		t.Errorf("Expected one generation warning, got %+v", ws)
+	}
+}
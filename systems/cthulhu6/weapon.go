@@ -0,0 +1,178 @@
+package cthulhu6
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"charaxiv/dice"
+)
+
+//go:embed weapons.json
+var weaponsJSON []byte
+
+// Weapon is a weapon carried by an investigator
+type Weapon struct {
+	Name        string `json:"name"`
+	Skill       string `json:"skill"`       // linked skill key, or a custom skill name
+	Damage      string `json:"damage"`      // dice notation, e.g. "1D10+2"
+	Range       string `json:"range"`       // e.g. "15m", "タッチ"
+	Attacks     string `json:"attacks"`     // attacks per round, e.g. "2", "1/2"
+	Ammo        int    `json:"ammo"`        // rounds per load; 0 for none
+	Malfunction int    `json:"malfunction"` // 1D100 roll at or above which the weapon jams; 0 for none
+	HP          int    `json:"hp"`          // durability; 0 for none
+	Melee       bool   `json:"melee"`       // damage bonus applies
+}
+
+// CatalogWeapon is a built-in weapon
+type CatalogWeapon struct {
+	ID string `json:"id"`
+	Weapon
+}
+
+// WeaponCatalog is the built-in weapon catalog in display order
+var WeaponCatalog = mustLoadWeapons()
+
+func mustLoadWeapons() []CatalogWeapon {
+	var weapons []CatalogWeapon
+	if err := json.Unmarshal(weaponsJSON, &weapons); err != nil {
+		panic(fmt.Sprintf("cthulhu6: parse weapons.json: %v", err))
+	}
+	return weapons
+}
+
+// CatalogWeaponByID returns the catalog weapon with the given ID
+func CatalogWeaponByID(id string) (Weapon, bool) {
+	for _, w := range WeaponCatalog {
+		if w.ID == id {
+			return w.Weapon, true
+		}
+	}
+	return Weapon{}, false
+}
+
+// ValidateDamage checks that a damage expression can be rolled
+func ValidateDamage(damage string) error {
+	e, err := dice.Parse(damage)
+	if err != nil {
+		return fmt.Errorf("damage %q: %w", damage, err)
+	}
+	if e.HasTarget() {
+		return fmt.Errorf("damage %q must not contain a comparison", damage)
+	}
+	return nil
+}
+
+// DamageExpr returns the damage expression to roll, adding the damage bonus for melee weapons
+func (w Weapon) DamageExpr(status *Status) string {
+	if !w.Melee {
+		return w.Damage
+	}
+	db := status.DamageBonus()
+	if db == "" || db == "+0" || db == "0" {
+		return w.Damage
+	}
+	if db[0] != '+' && db[0] != '-' {
+		db = "+" + db
+	}
+	return w.Damage + db
+}
+
+// Set updates one field from a form value. Damage must be valid dice notation;
+// numeric fields must be non-negative integers; melee accepts "true"/"on".
+func (w *Weapon) Set(field, value string) error {
+	value = strings.TrimSpace(value)
+	switch field {
+	case "name":
+		w.Name = value
+	case "skill":
+		w.Skill = value
+	case "damage":
+		if err := ValidateDamage(value); err != nil {
+			return err
+		}
+		w.Damage = value
+	case "range":
+		w.Range = value
+	case "attacks":
+		w.Attacks = value
+	case "ammo", "malfunction", "hp":
+		n := 0
+		if value != "" {
+			var err error
+			if n, err = strconv.Atoi(value); err != nil || n < 0 {
+				return fmt.Errorf("weapon %s %q must be a non-negative integer", field, value)
+			}
+		}
+		switch field {
+		case "ammo":
+			w.Ammo = n
+		case "malfunction":
+			w.Malfunction = n
+		case "hp":
+			w.HP = n
+		}
+	case "melee":
+		w.Melee = value == "true" || value == "on"
+	default:
+		return fmt.Errorf("unknown weapon field %q", field)
+	}
+	return nil
+}
+
+// MaxDamageLog is the number of damage rolls kept on a character
+const MaxDamageLog = 20
+
+// DamageRoll records a single damage roll
+type DamageRoll struct {
+	Weapon string    `json:"weapon"`
+	Expr   string    `json:"expr"`   // rolled expression including the damage bonus
+	Detail string    `json:"detail"` // e.g. "1D3[2]+1D4[3]"
+	Total  int       `json:"total"`  // never below 0
+	Time   time.Time `json:"time"`
+}
+
+// RollDamage rolls the weapon's damage, adding the damage bonus for melee weapons
+func RollDamage(r *dice.Roller, w Weapon, status *Status) (DamageRoll, error) {
+	expr := w.DamageExpr(status)
+	if err := ValidateDamage(expr); err != nil {
+		return DamageRoll{}, err
+	}
+	res, err := r.Roll(expr)
+	if err != nil {
+		return DamageRoll{}, fmt.Errorf("roll damage %s: %w", w.Name, err)
+	}
+	return DamageRoll{
+		Weapon: w.Name,
+		Expr:   res.Notation,
+		Detail: res.Detail,
+		Total:  max(res.Total, 0),
+		Time:   time.Now(),
+	}, nil
+}
+
+// AppendDamageRolls appends a damage roll, dropping the oldest beyond MaxDamageLog
+func AppendDamageRolls(log []DamageRoll, d DamageRoll) []DamageRoll {
+	log = append(log, d)
+	if len(log) > MaxDamageLog {
+		log = log[len(log)-MaxDamageLog:]
+	}
+	return log
+}
+
+// WeaponSkillValue returns the total of a weapon's linked skill, looking up
+// single skills first and then custom skills by name
+func (s *Status) WeaponSkillValue(skills *Skills, key string) (int, bool) {
+	if v, ok := s.SkillValue(skills, key); ok {
+		return v, true
+	}
+	for _, cs := range skills.Custom {
+		if cs.Name != "" && cs.Name == key {
+			return cs.Total(), true
+		}
+	}
+	return 0, false
+}
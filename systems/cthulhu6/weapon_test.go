@@ -0,0 +1,64 @@
+package cthulhu6
+
+import (
+	"testing"
+
+	"charaxiv/dice"
+)
+
+type fixedSource int
+
+func (f fixedSource) IntN(n int) int { return min(int(f), n-1) }
+
+func TestWeaponCatalog(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	seen := map[string]bool{}
+	for _, w := range WeaponCatalog {
+		if w.ID == "" || w.Name == "" || seen[w.ID] {
+			t.Errorf("Invalid or duplicate weapon %+v", w)
+		}
+		seen[w.ID] = true
+		if _, ok := status.WeaponSkillValue(skills, w.Skill); !ok {
+			t.Errorf("%s: unknown skill %q", w.ID, w.Skill)
+		}
+		if err := ValidateDamage(w.Damage); err != nil {
+			t.Errorf("%s: %v", w.ID, err)
+		}
+	}
+}
+
+func TestDamageExpr(t *testing.T) {
+	tests := []struct {
+		db    string
+		melee bool
+		want  string
+	}{
+		{"+1d4", true, "1D3+1d4"},
+		{"-1d6", true, "1D3-1d6"},
+		{"+0", true, "1D3"},
+		{"1D6", true, "1D3+1D6"},
+		{"+1d4", false, "1D3"},
+	}
+	for _, tt := range tests {
+		status := NewStatus()
+		status.DB = tt.db
+		w := Weapon{Damage: "1D3", Melee: tt.melee}
+		if got := w.DamageExpr(status); got != tt.want {
+			t.Errorf("DamageExpr(db=%s, melee=%v) = %s, want %s", tt.db, tt.melee, got, tt.want)
+		}
+	}
+}
+
+func TestRollDamageNeverNegative(t *testing.T) {
+	status := NewStatus()
+	status.DB = "-1d6"
+	// 1D3 rolls 3, -1D6 rolls 6
+	r := dice.NewRoller(fixedSource(5))
+	d, err := RollDamage(r, Weapon{Name: "こぶし", Damage: "1D3", Melee: true}, status)
+	if err != nil {
+		t.Fatalf("RollDamage: %v", err)
+	}
+	if d.Total != 0 {
+		t.Errorf("Expected damage clamped to 0, got %d (%s)", d.Total, d.Detail)
+	}
+}
@@ -0,0 +1,133 @@
+package cthulhu7
+
+import (
+	"fmt"
+	"sort"
+
+	"charaxiv/templates/shared"
+)
+
+// BuildSheetState converts status and skills to template types
+func BuildSheetState(pc shared.PageContext, status *Status, skills *Skills) shared.Cthulhu7SheetState {
+	build, db := status.BuildAndDamageBonus()
+	job, hobby := status.RemainingPoints(skills)
+	state := shared.Cthulhu7SheetState{
+		PC:          pc,
+		Luck:        status.Luck,
+		Age:         status.Age,
+		MaxSanity:   status.MaxSanity(skills),
+		Build:       build,
+		DamageBonus: db,
+		Move:        status.Move(),
+		JobFormula:  status.JobFormula,
+		JobPoints:   status.JobPoints(),
+		HobbyPoints: status.HobbyPoints(),
+		Extra:       shared.SkillExtra{Job: skills.Extra.Job, Hobby: skills.Extra.Hobby},
+		Remaining:   shared.SkillPoints{Job: job, Hobby: hobby},
+	}
+	if state.JobFormula == "" {
+		state.JobFormula = JobFormulas[0]
+	}
+	for _, f := range JobFormulas {
+		state.JobFormulas = append(state.JobFormulas, shared.Option{Value: f, Label: FormulaLabel(f)})
+	}
+
+	for _, key := range VariableOrder {
+		v := status.Variables[key]
+		state.Variables = append(state.Variables, shared.Cthulhu7Variable{
+			Key:  key,
+			Base: v.Base,
+			Perm: v.Perm,
+			Temp: v.Temp,
+			Min:  v.Min,
+			Max:  v.Max,
+		})
+	}
+
+	defaults := status.DefaultParameters()
+	for _, key := range ParameterOrder {
+		state.Parameters = append(state.Parameters, shared.StatusParameter{
+			Key:          key,
+			Value:        status.Parameters[key],
+			DefaultValue: defaults[key],
+		})
+	}
+
+	for _, cat := range CategoryOrder {
+		state.Categories = append(state.Categories, BuildSkillCategory(status, cat, skills.Categories[cat]))
+	}
+
+	for i, cs := range skills.Custom {
+		state.Custom = append(state.Custom, shared.Cthulhu7SkillRow{
+			ID:     fmt.Sprintf("custom-%d", i),
+			Path:   fmt.Sprintf("/api/skill/custom/%d", i),
+			Label:  cs.Name,
+			Init:   1,
+			Job:    cs.Job,
+			Hobby:  cs.Hobby,
+			Growth: cs.Growth,
+			Custom: true,
+		})
+	}
+	return state
+}
+
+// BuildSkillCategory converts one category to template types in display order
+func BuildSkillCategory(status *Status, cat SkillCategory, catData SkillCategoryData) shared.Cthulhu7SkillCategory {
+	keys := make([]string, 0, len(catData.Skills))
+	for key := range catData.Skills {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return catData.Skills[keys[i]].Order < catData.Skills[keys[j]].Order
+	})
+
+	out := shared.Cthulhu7SkillCategory{Name: string(cat)}
+	for _, key := range keys {
+		out.Skills = append(out.Skills, BuildSkill(status, key, catData.Skills[key]))
+	}
+	return out
+}
+
+// BuildSkill converts a built-in skill to template types
+func BuildSkill(status *Status, key string, skill Skill) shared.Cthulhu7Skill {
+	d, _ := SkillDefByKey(key)
+	out := shared.Cthulhu7Skill{
+		Key:   key,
+		Path:  "/api/skill/" + key,
+		Multi: skill.IsMulti(),
+	}
+	switch {
+	case skill.IsSingle():
+		out.Rows = []shared.Cthulhu7SkillRow{{
+			ID:        "skill-" + key,
+			Path:      out.Path,
+			Label:     key,
+			Init:      status.SkillInitialValue(key),
+			Job:       skill.Single.Job,
+			Hobby:     skill.Single.Hobby,
+			Growth:    skill.Single.Growth,
+			Essential: d.Essential,
+		}}
+	case skill.IsMulti():
+		for i, g := range skill.Multi.Genres {
+			label := key
+			if g.Label != "" {
+				label = fmt.Sprintf("%s(%s)", key, g.Label)
+			}
+			out.Rows = append(out.Rows, shared.Cthulhu7SkillRow{
+				ID:        fmt.Sprintf("genre-%s-%d", key, i),
+				Path:      fmt.Sprintf("%s/genre/%d", out.Path, i),
+				Label:     label,
+				Init:      status.GenreInitialValue(key, g.Label),
+				Job:       g.Job,
+				Hobby:     g.Hobby,
+				Growth:    g.Growth,
+				Essential: d.Essential,
+				Genre:     true,
+				GenreName: g.Label,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+// Package cthulhu7 implements the Call of Cthulhu 7th Edition game system.
+package cthulhu7
+
+import (
+	"fmt"
+	"strings"
+
+	"charaxiv/dice"
+)
+
+// Variable represents a percentile characteristic with base, perm, and temp modifiers
+type Variable struct {
+	Base int    `json:"base"`
+	Perm int    `json:"perm"`
+	Temp int    `json:"temp"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+	Dice string `json:"dice"` // roll formula for the base, e.g. "3D6*5"
+}
+
+// Sum returns the total value of the variable
+func (v Variable) Sum() int {
+	return v.Base + v.Perm + v.Temp
+}
+
+// Half returns the hard success threshold
+func (v Variable) Half() int {
+	return v.Sum() / 2
+}
+
+// Fifth returns the extreme success threshold
+func (v Variable) Fifth() int {
+	return v.Sum() / 5
+}
+
+// VariableOrder is the display order of characteristics
+var VariableOrder = []string{"STR", "CON", "POW", "DEX", "APP", "SIZ", "INT", "EDU"}
+
+// ParameterOrder is the display order of parameters
+var ParameterOrder = []string{"HP", "MP", "SAN"}
+
+// LuckDice is the roll formula for 幸運
+const LuckDice = "3D6*5"
+
+// JobFormulas are the 職業P formulas an occupation can use; the first is the default
+var JobFormulas = []string{"EDU*4", "EDU*2+STR*2", "EDU*2+DEX*2", "EDU*2+APP*2", "EDU*2+POW*2"}
+
+// HobbyFormula is the 興味P formula
+const HobbyFormula = "INT*2"
+
+// FormulaLabel formats a formula for display, e.g. "EDU×2+DEX×2"
+func FormulaLabel(formula string) string {
+	return strings.ReplaceAll(formula, "*", "×")
+}
+
+// Status represents the status section for CoC 7th edition
+type Status struct {
+	Variables  map[string]Variable `json:"variables"`
+	Parameters map[string]*int     `json:"parameters"` // nil means use default
+	Luck       int                 `json:"luck"`
+	Age        int                 `json:"age"`
+	JobFormula string              `json:"jobFormula"` // one of JobFormulas
+}
+
+// NewStatus creates a new status with default values.
+// Defaults are the expected values for each dice roll, rounded up:
+//   - 3D6×5 (STR, CON, POW, DEX, APP, 幸運): 55
+//   - (2D6+6)×5 (SIZ, INT, EDU): 65
+func NewStatus() *Status {
+	return &Status{
+		Variables: map[string]Variable{
+			"STR": {Base: 55, Min: 15, Max: 90, Dice: "3D6*5"},
+			"CON": {Base: 55, Min: 15, Max: 90, Dice: "3D6*5"},
+			"POW": {Base: 55, Min: 15, Max: 90, Dice: "3D6*5"},
+			"DEX": {Base: 55, Min: 15, Max: 90, Dice: "3D6*5"},
+			"APP": {Base: 55, Min: 15, Max: 90, Dice: "3D6*5"},
+			"SIZ": {Base: 65, Min: 40, Max: 90, Dice: "(2D6+6)*5"},
+			"INT": {Base: 65, Min: 40, Max: 90, Dice: "(2D6+6)*5"},
+			"EDU": {Base: 65, Min: 40, Max: 90, Dice: "(2D6+6)*5"},
+		},
+		Parameters: map[string]*int{
+			"HP":  nil,
+			"MP":  nil,
+			"SAN": nil,
+		},
+		Luck:       55,
+		Age:        25,
+		JobFormula: JobFormulas[0],
+	}
+}
+
+// DefaultParameters returns the default parameter values derived from characteristics
+func (s *Status) DefaultParameters() map[string]int {
+	return map[string]int{
+		"HP":  (s.Variables["CON"].Sum() + s.Variables["SIZ"].Sum()) / 10,
+		"MP":  s.Variables["POW"].Sum() / 5,
+		"SAN": s.Variables["POW"].Sum(),
+	}
+}
+
+// EffectiveParameter returns the parameter value or its default
+func (s *Status) EffectiveParameter(key string) int {
+	if val := s.Parameters[key]; val != nil {
+		return *val
+	}
+	return s.DefaultParameters()[key]
+}
+
+// BuildAndDamageBonus returns ビルド and the damage bonus for STR+SIZ.
+// Every 80 points past 364 adds one to ビルド and another 1D6.
+func (s *Status) BuildAndDamageBonus() (int, string) {
+	sum := s.Variables["STR"].Sum() + s.Variables["SIZ"].Sum()
+	switch {
+	case sum <= 64:
+		return -2, "-2"
+	case sum <= 84:
+		return -1, "-1"
+	case sum <= 124:
+		return 0, "0"
+	case sum <= 164:
+		return 1, "+1D4"
+	case sum <= 204:
+		return 2, "+1D6"
+	}
+	extra := (sum - 205) / 80
+	return 3 + extra, fmt.Sprintf("+%dD6", 2+extra)
+}
+
+// Move returns the movement rate from STR, DEX and SIZ, reduced by one for
+// each decade of age from 40
+func (s *Status) Move() int {
+	str, dex, siz := s.Variables["STR"].Sum(), s.Variables["DEX"].Sum(), s.Variables["SIZ"].Sum()
+	mov := 8
+	switch {
+	case str < siz && dex < siz:
+		mov = 7
+	case str > siz && dex > siz:
+		mov = 9
+	}
+	if s.Age >= 40 {
+		mov -= (s.Age - 30) / 10
+	}
+	return max(mov, 0)
+}
+
+// JobPoints returns 職業P under the character's formula
+func (s *Status) JobPoints() int {
+	formula := s.JobFormula
+	if formula == "" {
+		formula = JobFormulas[0]
+	}
+	n, _ := EvalFormula(formula, s)
+	return n
+}
+
+// HobbyPoints returns 興味P
+func (s *Status) HobbyPoints() int {
+	n, _ := EvalFormula(HobbyFormula, s)
+	return n
+}
+
+// MaxSanity returns the SAN ceiling: 99 minus クトゥルフ神話
+func (s *Status) MaxSanity(skills *Skills) int {
+	mythos, _ := s.SkillValue(skills, MythosSkill)
+	return 99 - mythos
+}
+
+// SetVariableBase sets a characteristic base within its range
+func (s *Status) SetVariableBase(key string, value int) error {
+	v, ok := s.Variables[key]
+	if !ok {
+		return fmt.Errorf("unknown characteristic %q", key)
+	}
+	if value < v.Min || value > v.Max {
+		return fmt.Errorf("%s must be between %d and %d", key, v.Min, v.Max)
+	}
+	v.Base = value
+	s.Variables[key] = v
+	return nil
+}
+
+// RollVariable rolls a characteristic's dice formula and clamps the total to its range
+func RollVariable(r *dice.Roller, key string, v Variable) (int, error) {
+	res, err := r.Roll(v.Dice)
+	if err != nil {
+		return 0, fmt.Errorf("roll %s: %w", key, err)
+	}
+	return min(max(res.Total, v.Min), v.Max), nil
+}
+
+// EvalFormula evaluates a formula such as "DEX/2" or "EDU*2+STR*2" against the
+// characteristic totals. Formulas may use integers, characteristic keys and
+// arithmetic, but no dice.
+func EvalFormula(formula string, status *Status) (int, error) {
+	return dice.EvalFormula(formula, func(key string) (int, bool) {
+		v, ok := status.Variables[key]
+		return v.Sum(), ok
+	})
+}
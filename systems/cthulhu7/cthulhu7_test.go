@@ -0,0 +1,101 @@
+package cthulhu7
+
+import "testing"
+
+// setVar sets a characteristic base for tests
+func setVar(s *Status, key string, base int) {
+	v := s.Variables[key]
+	v.Base = base
+	s.Variables[key] = v
+}
+
+func TestDefaultDerivedValues(t *testing.T) {
+	s := NewStatus() // STR/CON/POW/DEX/APP 55, SIZ/INT/EDU 65
+	params := s.DefaultParameters()
+	if params["HP"] != 12 || params["MP"] != 11 || params["SAN"] != 55 {
+		t.Errorf("Unexpected parameters %v", params)
+	}
+	if v := s.Variables["DEX"]; v.Half() != 27 || v.Fifth() != 11 {
+		t.Errorf("DEX half/fifth = %d/%d, want 27/11", v.Half(), v.Fifth())
+	}
+	if got := s.JobPoints(); got != 260 {
+		t.Errorf("職業P = %d, want 260", got)
+	}
+	if got := s.HobbyPoints(); got != 130 {
+		t.Errorf("興味P = %d, want 130", got)
+	}
+	s.JobFormula = "EDU*2+DEX*2"
+	if got := s.JobPoints(); got != 240 {
+		t.Errorf("職業P (EDU×2+DEX×2) = %d, want 240", got)
+	}
+}
+
+func TestBuildAndDamageBonus(t *testing.T) {
+	tests := []struct {
+		str, siz int
+		build    int
+		db       string
+	}{
+		{15, 40, -2, "-2"},
+		{40, 40, -1, "-1"},
+		{55, 65, 0, "0"},
+		{80, 80, 1, "+1D4"},
+		{90, 90, 2, "+1D6"},
+		{150, 90, 3, "+2D6"},
+		{200, 90, 4, "+3D6"},
+	}
+	for _, tt := range tests {
+		s := NewStatus()
+		setVar(s, "STR", tt.str)
+		setVar(s, "SIZ", tt.siz)
+		build, db := s.BuildAndDamageBonus()
+		if build != tt.build || db != tt.db {
+			t.Errorf("STR %d SIZ %d: got %d %s, want %d %s", tt.str, tt.siz, build, db, tt.build, tt.db)
+		}
+	}
+}
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		str, dex, siz, age int
+		want               int
+	}{
+		{50, 50, 60, 25, 7},
+		{60, 50, 60, 25, 8},
+		{70, 70, 60, 25, 9},
+		{70, 70, 60, 45, 8},
+		{70, 70, 60, 82, 4},
+	}
+	for _, tt := range tests {
+		s := NewStatus()
+		setVar(s, "STR", tt.str)
+		setVar(s, "DEX", tt.dex)
+		setVar(s, "SIZ", tt.siz)
+		s.Age = tt.age
+		if got := s.Move(); got != tt.want {
+			t.Errorf("STR %d DEX %d SIZ %d age %d: MOV %d, want %d", tt.str, tt.dex, tt.siz, tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestSetVariableBase(t *testing.T) {
+	s := NewStatus()
+	if err := s.SetVariableBase("STR", 70); err != nil || s.Variables["STR"].Base != 70 {
+		t.Errorf("SetVariableBase(STR, 70) = %v, base %d", err, s.Variables["STR"].Base)
+	}
+	if err := s.SetVariableBase("SIZ", 30); err == nil {
+		t.Error("Expected SIZ 30 to be out of range")
+	}
+	if err := s.SetVariableBase("LUK", 50); err == nil {
+		t.Error("Expected unknown characteristic to be rejected")
+	}
+}
+
+func TestJobFormulasValid(t *testing.T) {
+	s := NewStatus()
+	for _, f := range append(JobFormulas, HobbyFormula) {
+		if _, err := EvalFormula(f, s); err != nil {
+			t.Errorf("%s: %v", f, err)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package cthulhu7
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+//go:embed skills.json
+var skillsJSON []byte
+
+// MythosSkill is the skill that lowers the SAN ceiling
+const MythosSkill = "クトゥルフ神話"
+
+// SkillCategory represents a skill category
+type SkillCategory string
+
+const (
+	SkillCategoryCombat        SkillCategory = "戦闘技能"
+	SkillCategoryInvestigation SkillCategory = "探索技能"
+	SkillCategoryAction        SkillCategory = "行動技能"
+	SkillCategorySocial        SkillCategory = "交渉技能"
+	SkillCategoryKnowledge     SkillCategory = "知識技能"
+)
+
+// CategoryOrder is the display order for skill categories
+var CategoryOrder = []SkillCategory{
+	SkillCategoryCombat,
+	SkillCategoryInvestigation,
+	SkillCategoryAction,
+	SkillCategorySocial,
+	SkillCategoryKnowledge,
+}
+
+// SkillDef defines a built-in skill
+type SkillDef struct {
+	Key          string            `json:"key"`
+	Category     SkillCategory     `json:"category"`
+	Order        int               `json:"order"`                  // display order within the category
+	Multi        bool              `json:"multi,omitempty"`        // has genres, e.g. 射撃, 芸術／製作
+	Initial      string            `json:"initial"`                // initial value formula, e.g. "20", "DEX/2"
+	Essential    bool              `json:"essential,omitempty"`    // shown in bold
+	Genres       []string          `json:"genres,omitempty"`       // genres a new sheet starts with
+	GenreInitial map[string]string `json:"genreInitial,omitempty"` // initial value of known genres
+}
+
+// SkillDefs is the built-in skill definitions in file order
+var SkillDefs = mustLoadSkillDefs()
+
+var skillDefsByKey = func() map[string]SkillDef {
+	m := make(map[string]SkillDef, len(SkillDefs))
+	for _, d := range SkillDefs {
+		m[d.Key] = d
+	}
+	return m
+}()
+
+func mustLoadSkillDefs() []SkillDef {
+	var defs []SkillDef
+	if err := json.Unmarshal(skillsJSON, &defs); err != nil {
+		panic(fmt.Sprintf("cthulhu7: parse skills.json: %v", err))
+	}
+	if err := validateSkillDefs(defs); err != nil {
+		panic(fmt.Sprintf("cthulhu7: skills.json: %v", err))
+	}
+	return defs
+}
+
+// validateSkillDefs checks keys, categories, orders and formulas
+func validateSkillDefs(defs []SkillDef) error {
+	keys := map[string]bool{}
+	orders := map[SkillCategory][]int{}
+	status := NewStatus()
+	for _, d := range defs {
+		if d.Key == "" || keys[d.Key] {
+			return fmt.Errorf("empty or duplicate key %q", d.Key)
+		}
+		keys[d.Key] = true
+		if !slices.Contains(CategoryOrder, d.Category) {
+			return fmt.Errorf("%s: unknown category %q", d.Key, d.Category)
+		}
+		if slices.Contains(orders[d.Category], d.Order) {
+			return fmt.Errorf("%s: duplicate order %d in %s", d.Key, d.Order, d.Category)
+		}
+		orders[d.Category] = append(orders[d.Category], d.Order)
+		if (len(d.Genres) > 0 || len(d.GenreInitial) > 0) && !d.Multi {
+			return fmt.Errorf("%s: genres on a single skill", d.Key)
+		}
+		if _, err := EvalFormula(d.Initial, status); err != nil {
+			return fmt.Errorf("%s: %w", d.Key, err)
+		}
+		for genre, formula := range d.GenreInitial {
+			if _, err := EvalFormula(formula, status); err != nil {
+				return fmt.Errorf("%s(%s): %w", d.Key, genre, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SkillDefByKey returns the definition of a built-in skill
+func SkillDefByKey(key string) (SkillDef, bool) {
+	d, ok := skillDefsByKey[key]
+	return d, ok
+}
+
+// Allocation holds the points put into a skill
+type Allocation struct {
+	Job    int `json:"job"`    // 職業P
+	Hobby  int `json:"hobby"`  // 興味P
+	Growth int `json:"growth"` // gained through growth checks
+}
+
+// Sum returns total allocated points
+func (a Allocation) Sum() int {
+	return a.Job + a.Hobby + a.Growth
+}
+
+// Adjust changes one field by delta. Job and hobby points cannot go below zero.
+func (a *Allocation) Adjust(field string, delta int) error {
+	switch field {
+	case "job":
+		a.Job = max(a.Job+delta, 0)
+	case "hobby":
+		a.Hobby = max(a.Hobby+delta, 0)
+	case "growth":
+		a.Growth += delta
+	default:
+		return fmt.Errorf("unknown skill field %q", field)
+	}
+	return nil
+}
+
+// SkillGenre represents one specialty within a multi-genre skill
+type SkillGenre struct {
+	Label string `json:"label"` // e.g., "拳銃" for 射撃
+	Allocation
+}
+
+// MultiSkill represents a skill with multiple specialties
+type MultiSkill struct {
+	Genres []SkillGenre `json:"genres"`
+}
+
+// Skill wraps either a single or multi skill (exactly one will be non-nil)
+type Skill struct {
+	Order  int         `json:"order"`
+	Single *Allocation `json:"single,omitempty"`
+	Multi  *MultiSkill `json:"multi,omitempty"`
+}
+
+// IsSingle returns true if this is a single skill
+func (s Skill) IsSingle() bool {
+	return s.Single != nil
+}
+
+// IsMulti returns true if this is a multi-genre skill
+func (s Skill) IsMulti() bool {
+	return s.Multi != nil
+}
+
+// SkillExtra represents extra skill points
+type SkillExtra struct {
+	Job   int `json:"job"`
+	Hobby int `json:"hobby"`
+}
+
+// SkillCategoryData represents a category of skills
+type SkillCategoryData struct {
+	Skills map[string]Skill `json:"skills"`
+	Order  int              `json:"order"`
+}
+
+// CustomSkill represents a user-defined skill (always single, starting at 1)
+type CustomSkill struct {
+	Name string `json:"name"`
+	Allocation
+}
+
+// Skills represents all skills for a character
+type Skills struct {
+	Categories map[SkillCategory]SkillCategoryData `json:"categories"`
+	Custom     []CustomSkill                       `json:"custom"`
+	Extra      SkillExtra                          `json:"extra"`
+}
+
+// NewSkills creates skills with default values from the skill definitions
+func NewSkills() *Skills {
+	skills := &Skills{
+		Categories: make(map[SkillCategory]SkillCategoryData, len(CategoryOrder)),
+		Custom:     []CustomSkill{},
+	}
+	for i, cat := range CategoryOrder {
+		skills.Categories[cat] = SkillCategoryData{Order: i, Skills: map[string]Skill{}}
+	}
+	for _, d := range SkillDefs {
+		skill := Skill{Order: d.Order}
+		if d.Multi {
+			skill.Multi = &MultiSkill{Genres: []SkillGenre{}}
+			for _, label := range d.Genres {
+				skill.Multi.Genres = append(skill.Multi.Genres, SkillGenre{Label: label})
+			}
+		} else {
+			skill.Single = &Allocation{}
+		}
+		skills.Categories[d.Category].Skills[d.Key] = skill
+	}
+	return skills
+}
+
+// Skill returns a built-in skill by key
+func (s *Skills) Skill(key string) (Skill, bool) {
+	d, ok := SkillDefByKey(key)
+	if !ok {
+		return Skill{}, false
+	}
+	skill, ok := s.Categories[d.Category].Skills[key]
+	return skill, ok
+}
+
+// SetSkill stores a built-in skill under its category
+func (s *Skills) SetSkill(key string, skill Skill) error {
+	d, ok := SkillDefByKey(key)
+	if !ok {
+		return fmt.Errorf("unknown skill %q", key)
+	}
+	s.Categories[d.Category].Skills[key] = skill
+	return nil
+}
+
+// SkillInitialValue returns the initial value for a skill based on characteristics.
+// Skills without a definition (custom skills) start at 1.
+func (s *Status) SkillInitialValue(key string) int {
+	d, ok := SkillDefByKey(key)
+	if !ok {
+		return 1
+	}
+	n, _ := EvalFormula(d.Initial, s)
+	return n
+}
+
+// GenreInitialValue returns the initial value for a genre of a multi skill,
+// e.g. 25 for 近接戦闘(格闘). Unknown genres use the skill's initial value.
+func (s *Status) GenreInitialValue(key, label string) int {
+	d, ok := SkillDefByKey(key)
+	if !ok {
+		return 1
+	}
+	formula, ok := d.GenreInitial[label]
+	if !ok {
+		formula = d.Initial
+	}
+	n, _ := EvalFormula(formula, s)
+	return n
+}
+
+// SkillValue returns the total of a single built-in skill
+func (s *Status) SkillValue(skills *Skills, key string) (int, bool) {
+	skill, ok := skills.Skill(key)
+	if !ok || !skill.IsSingle() {
+		return 0, false
+	}
+	return s.SkillInitialValue(key) + skill.Single.Sum(), true
+}
+
+// RemainingPoints calculates remaining 職業P and 興味P
+func (s *Status) RemainingPoints(skills *Skills) (job int, hobby int) {
+	job = s.JobPoints() + skills.Extra.Job
+	hobby = s.HobbyPoints() + skills.Extra.Hobby
+	spend := func(a Allocation) {
+		job -= a.Job
+		hobby -= a.Hobby
+	}
+	for _, catData := range skills.Categories {
+		for _, skill := range catData.Skills {
+			switch {
+			case skill.IsSingle():
+				spend(*skill.Single)
+			case skill.IsMulti():
+				for _, g := range skill.Multi.Genres {
+					spend(g.Allocation)
+				}
+			}
+		}
+	}
+	for _, cs := range skills.Custom {
+		spend(cs.Allocation)
+	}
+	return job, hobby
+}
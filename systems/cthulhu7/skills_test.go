@@ -0,0 +1,91 @@
+package cthulhu7
+
+import "testing"
+
+func TestSkillDefsValid(t *testing.T) {
+	if err := validateSkillDefs(SkillDefs); err != nil {
+		t.Fatalf("skills.json: %v", err)
+	}
+	bad := [][]SkillDef{
+		{{Key: "a", Category: SkillCategoryCombat, Initial: "1"}, {Key: "a", Category: SkillCategoryCombat, Order: 1, Initial: "1"}},
+		{{Key: "a", Category: "魔法技能", Initial: "1"}},
+		{{Key: "a", Category: SkillCategoryCombat, Initial: "1", GenreInitial: map[string]string{"x": "5"}}},
+		{{Key: "a", Category: SkillCategoryCombat, Multi: true, Initial: "1", GenreInitial: map[string]string{"x": "1D6"}}},
+	}
+	for i, defs := range bad {
+		if err := validateSkillDefs(defs); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestSkillInitialValues(t *testing.T) {
+	s := NewStatus()
+	setVar(s, "DEX", 70)
+	tests := []struct {
+		key, genre string
+		want       int
+	}{
+		{"回避", "", 35},
+		{"目星", "", 25},
+		{"信用", "", 0},
+		{"料理", "", 1}, // custom skills start at 1
+	}
+	for _, tt := range tests {
+		if got := s.SkillInitialValue(tt.key); got != tt.want {
+			t.Errorf("SkillInitialValue(%s) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+	genres := []struct {
+		key, genre string
+		want       int
+	}{
+		{"近接戦闘", "格闘", 25},
+		{"射撃", "ライフル／ショットガン", 25},
+		{"射撃", "拳銃", 20},
+		{"射撃", "吹き矢", 1},
+		{"母国語", "", 65},
+	}
+	for _, tt := range genres {
+		if got := s.GenreInitialValue(tt.key, tt.genre); got != tt.want {
+			t.Errorf("GenreInitialValue(%s, %s) = %d, want %d", tt.key, tt.genre, got, tt.want)
+		}
+	}
+}
+
+func TestRemainingPoints(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	skill, _ := skills.Skill("目星")
+	skill.Single.Adjust("job", 40)
+	skill.Single.Adjust("hobby", -5)
+	skill.Single.Adjust("growth", 3)
+	skills.SetSkill("目星", skill)
+
+	fight, _ := skills.Skill("近接戦闘")
+	fight.Multi.Genres[0].Adjust("hobby", 30)
+	skills.SetSkill("近接戦闘", fight)
+
+	skills.Custom = append(skills.Custom, CustomSkill{Name: "料理", Allocation: Allocation{Job: 10}})
+	skills.Extra.Hobby = 5
+
+	job, hobby := status.RemainingPoints(skills)
+	if job != 260-50 || hobby != 130+5-30 {
+		t.Errorf("RemainingPoints = %d, %d; want 210, 105", job, hobby)
+	}
+	if got, _ := status.SkillValue(skills, "目星"); got != 25+40+3 {
+		t.Errorf("目星 = %d, want 68", got)
+	}
+	if err := skill.Single.Adjust("perm", 1); err == nil {
+		t.Error("Expected unknown field to be rejected")
+	}
+}
+
+func TestMaxSanity(t *testing.T) {
+	status, skills := NewStatus(), NewSkills()
+	mythos, _ := skills.Skill(MythosSkill)
+	mythos.Single.Growth = 8
+	skills.SetSkill(MythosSkill, mythos)
+	if got := status.MaxSanity(skills); got != 91 {
+		t.Errorf("MaxSanity = %d, want 91", got)
+	}
+}
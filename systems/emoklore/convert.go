@@ -0,0 +1,64 @@
+package emoklore
+
+import (
+	"fmt"
+
+	"charaxiv/templates/shared"
+)
+
+// BuildSheetState converts status and skills to template types
+func BuildSheetState(pc shared.PageContext, status *Status, skills *Skills) shared.EmokloreSheetState {
+	state := shared.EmokloreSheetState{
+		PC:               pc,
+		AbilityRemaining: status.RemainingAbilityPoints(),
+		Initiative:       status.Initiative(),
+		Resonance:        status.Resonance,
+		SkillRemaining:   skills.RemainingPoints(),
+	}
+
+	groups := make(map[string]int, len(AbilityOrder))
+	for _, key := range AbilityOrder {
+		state.Abilities = append(state.Abilities, shared.EmokloreAbility{Key: key, Value: status.Abilities[key]})
+		state.AbilityOptions = append(state.AbilityOptions, shared.Option{Value: key, Label: key})
+		groups[key] = len(state.Groups)
+		state.Groups = append(state.Groups, shared.EmokloreSkillGroup{Ability: key})
+	}
+
+	defaults := status.DefaultParameters()
+	for _, key := range ParameterOrder {
+		state.Parameters = append(state.Parameters, shared.StatusParameter{
+			Key:          key,
+			Value:        status.Parameters[key],
+			DefaultValue: defaults[key],
+		})
+	}
+
+	for _, e := range EmotionKinds {
+		state.Emotions = append(state.Emotions, shared.EmokloreEmotion{Key: e.Key, Label: e.Label, Value: status.Emotions.Get(e.Key)})
+	}
+
+	for _, d := range SkillDefs {
+		g := &state.Groups[groups[d.Ability]]
+		g.Skills = append(g.Skills, buildSkillRow(status, "skill-"+d.Key, "/api/skill/"+d.Key, d.Key, d.Ability, skills.Levels[d.Key], false))
+	}
+
+	for i, cs := range skills.Custom {
+		state.Custom = append(state.Custom, buildSkillRow(status, fmt.Sprintf("custom-%d", i), fmt.Sprintf("/api/skill/custom/%d", i), cs.Name, cs.Ability, cs.Level, true))
+	}
+	return state
+}
+
+// buildSkillRow converts one skill to its template row
+func buildSkillRow(status *Status, id, path, label, ability string, level int, custom bool) shared.EmokloreSkillRow {
+	value := status.Abilities[ability]
+	return shared.EmokloreSkillRow{
+		ID:           id,
+		Path:         path,
+		Label:        label,
+		Ability:      ability,
+		AbilityValue: value,
+		Level:        level,
+		Command:      CheckCommand(value, level),
+		Custom:       custom,
+	}
+}
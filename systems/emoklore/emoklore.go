@@ -0,0 +1,133 @@
+// Package emoklore implements the エモクロアTRPG game system.
+package emoklore
+
+import "fmt"
+
+// AbilityOrder is the display order of the eight abilities (能力値)
+var AbilityOrder = []string{"身体", "器用", "精神", "五感", "知力", "魅力", "社会", "運勢"}
+
+// ParameterOrder is the display order of parameters
+var ParameterOrder = []string{"HP", "MP"}
+
+const (
+	AbilityMin    = 1
+	AbilityMax    = 6
+	AbilityBudget = 28 // points distributed across the eight abilities at creation
+
+	ResonanceMin = 1 // 共鳴 starts at 1
+	ResonanceMax = 10
+)
+
+// Emotions are the character's resonance emotions (共鳴感情)
+type Emotions struct {
+	Surface string `json:"surface"` // 表の感情
+	Hidden  string `json:"hidden"`  // 裏の感情
+	Roots   string `json:"roots"`   // ルーツ
+}
+
+// EmotionKinds are the emotion fields in display order with their labels
+var EmotionKinds = []struct{ Key, Label string }{
+	{"surface", "表の感情"},
+	{"hidden", "裏の感情"},
+	{"roots", "ルーツ"},
+}
+
+// Set sets an emotion by kind key
+func (e *Emotions) Set(kind, value string) error {
+	switch kind {
+	case "surface":
+		e.Surface = value
+	case "hidden":
+		e.Hidden = value
+	case "roots":
+		e.Roots = value
+	default:
+		return fmt.Errorf("unknown emotion %q", kind)
+	}
+	return nil
+}
+
+// Get returns an emotion by kind key
+func (e Emotions) Get(kind string) string {
+	switch kind {
+	case "surface":
+		return e.Surface
+	case "hidden":
+		return e.Hidden
+	case "roots":
+		return e.Roots
+	}
+	return ""
+}
+
+// Status represents the ability section of an Emoklore character
+type Status struct {
+	Abilities  map[string]int  `json:"abilities"`
+	Parameters map[string]*int `json:"parameters"` // current values; nil means full
+	Resonance  int             `json:"resonance"`  // 共鳴
+	Emotions   Emotions        `json:"emotions"`
+}
+
+// NewStatus creates a new status with every ability at 3 and 共鳴 at 1
+func NewStatus() *Status {
+	s := &Status{
+		Abilities:  make(map[string]int, len(AbilityOrder)),
+		Parameters: map[string]*int{"HP": nil, "MP": nil},
+		Resonance:  ResonanceMin,
+	}
+	for _, key := range AbilityOrder {
+		s.Abilities[key] = 3
+	}
+	return s
+}
+
+// DefaultParameters returns the maximum parameters: HP is 身体+10 and MP is 精神+10
+func (s *Status) DefaultParameters() map[string]int {
+	return map[string]int{
+		"HP": s.Abilities["身体"] + 10,
+		"MP": s.Abilities["精神"] + 10,
+	}
+}
+
+// EffectiveParameter returns the current parameter value or its maximum
+func (s *Status) EffectiveParameter(key string) int {
+	if val := s.Parameters[key]; val != nil {
+		return *val
+	}
+	return s.DefaultParameters()[key]
+}
+
+// Initiative returns 行動値: 器用+五感
+func (s *Status) Initiative() int {
+	return s.Abilities["器用"] + s.Abilities["五感"]
+}
+
+// RemainingAbilityPoints returns the creation points not yet distributed
+func (s *Status) RemainingAbilityPoints() int {
+	remaining := AbilityBudget
+	for _, key := range AbilityOrder {
+		remaining -= s.Abilities[key]
+	}
+	return remaining
+}
+
+// SetAbility sets an ability within its range
+func (s *Status) SetAbility(key string, value int) error {
+	if _, ok := s.Abilities[key]; !ok {
+		return fmt.Errorf("unknown ability %q", key)
+	}
+	if value < AbilityMin || value > AbilityMax {
+		return fmt.Errorf("%s must be between %d and %d", key, AbilityMin, AbilityMax)
+	}
+	s.Abilities[key] = value
+	return nil
+}
+
+// SetResonance sets 共鳴 within its range
+func (s *Status) SetResonance(value int) error {
+	if value < ResonanceMin || value > ResonanceMax {
+		return fmt.Errorf("共鳴 must be between %d and %d", ResonanceMin, ResonanceMax)
+	}
+	s.Resonance = value
+	return nil
+}
@@ -0,0 +1,108 @@
+package emoklore
+
+import (
+	"testing"
+
+	"charaxiv/templates/shared"
+)
+
+func TestDefaultDerivedValues(t *testing.T) {
+	s := NewStatus()
+	s.Abilities["身体"] = 5
+	s.Abilities["精神"] = 2
+	s.Abilities["器用"] = 4
+	params := s.DefaultParameters()
+	if params["HP"] != 15 || params["MP"] != 12 {
+		t.Errorf("Unexpected parameters %v", params)
+	}
+	if got := s.Initiative(); got != 7 {
+		t.Errorf("行動値 = %d, want 7", got)
+	}
+	if got := s.RemainingAbilityPoints(); got != AbilityBudget-26 {
+		t.Errorf("Remaining ability points = %d, want %d", got, AbilityBudget-26)
+	}
+}
+
+func TestSetAbilityRange(t *testing.T) {
+	s := NewStatus()
+	if err := s.SetAbility("身体", 6); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := s.SetAbility("身体", 7); err == nil {
+		t.Error("Expected 7 to be out of range")
+	}
+	if err := s.SetAbility("筋力", 3); err == nil {
+		t.Error("Expected an unknown ability to be rejected")
+	}
+	if err := s.SetResonance(0); err == nil {
+		t.Error("Expected 共鳴 0 to be out of range")
+	}
+}
+
+func TestSkillPoints(t *testing.T) {
+	skills := NewSkills()
+	skills.SetLevel("知覚", 2)
+	skills.SetLevel("交渉", 1)
+	skills.Custom = append(skills.Custom, CustomSkill{Name: "料理", Ability: "器用", Level: 3})
+	if got := skills.SpentPoints(); got != 3+1+6 {
+		t.Errorf("Spent points = %d, want 10", got)
+	}
+	if got := skills.RemainingPoints(); got != 0 {
+		t.Errorf("Remaining points = %d, want 0", got)
+	}
+	if err := skills.SetLevel("知覚", SkillLevelMax+1); err == nil {
+		t.Error("Expected a level above the maximum to be rejected")
+	}
+	if err := skills.SetLevel("剣術", 1); err == nil {
+		t.Error("Expected an unknown skill to be rejected")
+	}
+}
+
+func TestCheckCommand(t *testing.T) {
+	tests := []struct {
+		ability, level int
+		want           string
+	}{
+		{3, 0, "1DM<=3"},
+		{3, 1, "1DM<=4"},
+		{4, 2, "2DM<=6"},
+		{6, 3, "3DM<=9"},
+	}
+	for _, tt := range tests {
+		if got := CheckCommand(tt.ability, tt.level); got != tt.want {
+			t.Errorf("CheckCommand(%d, %d) = %s, want %s", tt.ability, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestBuildSheetStateGroupsSkills(t *testing.T) {
+	s := NewStatus()
+	skills := NewSkills()
+	skills.SetLevel("知覚", 2)
+	skills.Custom = append(skills.Custom, CustomSkill{Name: "料理", Ability: "器用", Level: 1})
+	state := BuildSheetState(shared.NewPageContext(), s, skills)
+
+	if len(state.Groups) != len(AbilityOrder) {
+		t.Fatalf("Expected one group per ability, got %d", len(state.Groups))
+	}
+	var found bool
+	for _, g := range state.Groups {
+		for _, row := range g.Skills {
+			if row.Ability != g.Ability {
+				t.Errorf("%s grouped under %s", row.Label, g.Ability)
+			}
+			if row.Label == "知覚" {
+				found = true
+				if row.Command != "2DM<=5" {
+					t.Errorf("知覚 check = %s, want 2DM<=5", row.Command)
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("知覚 missing from the skill groups")
+	}
+	if len(state.Custom) != 1 || state.Custom[0].Path != "/api/skill/custom/0" || state.Custom[0].Command != "1DM<=4" {
+		t.Errorf("Unexpected custom rows %+v", state.Custom)
+	}
+}
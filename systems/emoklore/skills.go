@@ -0,0 +1,120 @@
+package emoklore
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+//go:embed skills.json
+var skillsJSON []byte
+
+const (
+	SkillLevelMax    = 3
+	SkillPointBudget = 10 // skill points spent on levels at creation
+)
+
+// SkillDef defines a built-in skill and the ability it is checked with
+type SkillDef struct {
+	Key     string `json:"key"`
+	Ability string `json:"ability"`
+}
+
+// SkillDefs is the built-in skill definitions in display order
+var SkillDefs = mustLoadSkillDefs()
+
+func mustLoadSkillDefs() []SkillDef {
+	var defs []SkillDef
+	if err := json.Unmarshal(skillsJSON, &defs); err != nil {
+		panic(fmt.Sprintf("emoklore: parse skills.json: %v", err))
+	}
+	seen := map[string]bool{}
+	for _, d := range defs {
+		if d.Key == "" || seen[d.Key] {
+			panic(fmt.Sprintf("emoklore: skills.json: empty or duplicate key %q", d.Key))
+		}
+		seen[d.Key] = true
+		if !slices.Contains(AbilityOrder, d.Ability) {
+			panic(fmt.Sprintf("emoklore: skills.json: %s: unknown ability %q", d.Key, d.Ability))
+		}
+	}
+	return defs
+}
+
+// SkillDefByKey returns the definition of a built-in skill
+func SkillDefByKey(key string) (SkillDef, bool) {
+	i := slices.IndexFunc(SkillDefs, func(d SkillDef) bool { return d.Key == key })
+	if i < 0 {
+		return SkillDef{}, false
+	}
+	return SkillDefs[i], true
+}
+
+// SkillCost returns the skill points a level costs in total: 1, 3 and 6 for levels 1-3
+func SkillCost(level int) int {
+	return level * (level + 1) / 2
+}
+
+// CheckDice returns the number of D10s rolled for a check: one per skill level,
+// and one for an unlearned skill
+func CheckDice(level int) int {
+	return max(level, 1)
+}
+
+// CheckTarget returns the value each die must roll at or under: ability plus skill level
+func CheckTarget(ability, level int) int {
+	return ability + level
+}
+
+// CheckCommand returns the check in dice-bot notation, e.g. "2DM<=5"
+func CheckCommand(ability, level int) string {
+	return fmt.Sprintf("%dDM<=%d", CheckDice(level), CheckTarget(ability, level))
+}
+
+// CustomSkill is a user-defined skill checked with a chosen ability
+type CustomSkill struct {
+	Name    string `json:"name"`
+	Ability string `json:"ability"`
+	Level   int    `json:"level"`
+}
+
+// Skills holds skill levels; skills missing from Levels are unlearned
+type Skills struct {
+	Levels map[string]int `json:"levels"`
+	Custom []CustomSkill  `json:"custom"`
+}
+
+// NewSkills creates skills with every skill unlearned
+func NewSkills() *Skills {
+	return &Skills{Levels: map[string]int{}, Custom: []CustomSkill{}}
+}
+
+// SetLevel sets the level of a built-in skill
+func (s *Skills) SetLevel(key string, level int) error {
+	if _, ok := SkillDefByKey(key); !ok {
+		return fmt.Errorf("unknown skill %q", key)
+	}
+	if level < 0 || level > SkillLevelMax {
+		return fmt.Errorf("%s level must be between 0 and %d", key, SkillLevelMax)
+	}
+	s.Levels[key] = level
+	return nil
+}
+
+// SpentPoints returns the skill points spent on built-in and custom skills
+func (s *Skills) SpentPoints() int {
+	spent := 0
+	for _, level := range s.Levels {
+		spent += SkillCost(level)
+	}
+	for _, cs := range s.Custom {
+		spent += SkillCost(cs.Level)
+	}
+	return spent
+}
+
+// RemainingPoints returns the creation skill points not yet spent
+func (s *Skills) RemainingPoints() int {
+	return SkillPointBudget - s.SpentPoints()
+}
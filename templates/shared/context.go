@@ -10,6 +10,10 @@ type PageContext struct {
 	Memos map[string]string
 	// BasePath is the URL prefix for API routes (e.g., "/cthulhu6")
 	BasePath string
+	// Portraits is true when the system stores uploaded portraits
+	Portraits bool
+	// Portrait is the URL of the pinned portrait, empty when there is none
+	Portrait string
 }
 
 // IsReadOnly returns true if the character should be displayed in read-only mode.
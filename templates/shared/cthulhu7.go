@@ -0,0 +1,92 @@
+package shared
+
+// Cthulhu7Variable represents a percentile characteristic of a CoC 7th edition sheet
+type Cthulhu7Variable struct {
+	Key  string
+	Base int
+	Perm int
+	Temp int
+	Min  int
+	Max  int
+}
+
+// Sum returns the total value of the characteristic
+func (v Cthulhu7Variable) Sum() int {
+	return v.Base + v.Perm + v.Temp
+}
+
+// Half returns the hard success threshold
+func (v Cthulhu7Variable) Half() int {
+	return v.Sum() / 2
+}
+
+// Fifth returns the extreme success threshold
+func (v Cthulhu7Variable) Fifth() int {
+	return v.Sum() / 5
+}
+
+// Cthulhu7SkillRow is one row of the 7th edition skill table: a single skill,
+// a genre of a multi skill or a custom skill
+type Cthulhu7SkillRow struct {
+	ID        string // element ID prefix, unique on the page
+	Path      string // API prefix for the row, e.g. "/api/skill/目星"
+	Label     string
+	Init      int
+	Job       int
+	Hobby     int
+	Growth    int
+	Essential bool
+	Genre     bool   // row is a genre of a multi skill
+	GenreName string // editable genre label (genre rows only)
+	Custom    bool   // row is a custom skill with an editable name
+}
+
+// Total returns the skill value
+func (r Cthulhu7SkillRow) Total() int {
+	return r.Init + r.Job + r.Hobby + r.Growth
+}
+
+// Half returns the hard success threshold
+func (r Cthulhu7SkillRow) Half() int {
+	return r.Total() / 2
+}
+
+// Fifth returns the extreme success threshold
+func (r Cthulhu7SkillRow) Fifth() int {
+	return r.Total() / 5
+}
+
+// Cthulhu7Skill is a built-in skill; multi skills hold one row per genre
+type Cthulhu7Skill struct {
+	Key   string
+	Path  string // API prefix, e.g. "/api/skill/射撃"
+	Multi bool
+	Rows  []Cthulhu7SkillRow
+}
+
+// Cthulhu7SkillCategory is a group of skills in display order
+type Cthulhu7SkillCategory struct {
+	Name   string
+	Skills []Cthulhu7Skill
+}
+
+// Cthulhu7SheetState holds all data for rendering a CoC 7th edition sheet
+type Cthulhu7SheetState struct {
+	PC          PageContext
+	Variables   []Cthulhu7Variable
+	Luck        int
+	Age         int
+	Parameters  []StatusParameter
+	MaxSanity   int
+	Build       int
+	DamageBonus string
+	Move        int
+	JobFormula  string
+	JobFormulas []Option
+	JobPoints   int
+	HobbyPoints int
+	Categories  []Cthulhu7SkillCategory
+	Custom      []Cthulhu7SkillRow
+	Extra       SkillExtra
+	Remaining   SkillPoints
+}
@@ -0,0 +1,47 @@
+package shared
+
+// EmokloreAbility is one of the eight abilities of an Emoklore sheet
+type EmokloreAbility struct {
+	Key   string
+	Value int
+}
+
+// EmokloreEmotion is one resonance emotion field
+type EmokloreEmotion struct {
+	Key   string // form key, e.g. "surface"
+	Label string // e.g. "表の感情"
+	Value string
+}
+
+// EmokloreSkillRow is a built-in or custom skill with its check
+type EmokloreSkillRow struct {
+	ID           string // element ID prefix, unique on the page
+	Path         string // API prefix for the row, e.g. "/api/skill/知覚"
+	Label        string
+	Ability      string
+	AbilityValue int
+	Level        int
+	Command      string // check in dice-bot notation, e.g. "2DM<=5"
+	Custom       bool
+}
+
+// EmokloreSkillGroup is the skills checked with one ability
+type EmokloreSkillGroup struct {
+	Ability string
+	Skills  []EmokloreSkillRow
+}
+
+// EmokloreSheetState holds all data for rendering an Emoklore sheet
+type EmokloreSheetState struct {
+	PC               PageContext
+	Abilities        []EmokloreAbility
+	AbilityRemaining int
+	Parameters       []StatusParameter
+	Initiative       int
+	Resonance        int
+	Emotions         []EmokloreEmotion
+	Groups           []EmokloreSkillGroup
+	Custom           []EmokloreSkillRow
+	AbilityOptions   []Option // abilities a custom skill can use
+	SkillRemaining   int
+}
@@ -0,0 +1,18 @@
+package shared
+
+// SummaryStat is one labelled value on a character card
+type SummaryStat struct {
+	Label string
+	Value string
+}
+
+// CharacterSummary describes a character for listings and search, whatever its system
+type CharacterSummary struct {
+	System     string // game system ID, e.g. "cthulhu6"
+	SystemName string
+	ID         string
+	URL        string // sheet URL
+	Title      string // system-specific headline, e.g. the occupation
+	Stats      []SummaryStat
+	Memo       string // public memo; searched but not shown on the card
+}
@@ -8,6 +8,7 @@ type StatusVariable struct {
 	Temp int
 	Min  int
 	Max  int
+	Dice string // roll formula, e.g. "3D6"
 }
 
 // Sum returns the total value of the variable
@@ -122,12 +123,98 @@ func (s Skill) Allocated() int {
 	return 0
 }
 
+// StatRoll represents one entry of the ability score roll log
+type StatRoll struct {
+	Key    string
+	Detail string // rolled dice, e.g. "3D6[4,2,6]"
+	Value  int
+}
+
+// Option is a value/label pair for select inputs
+type Option struct {
+	Value string
+	Label string
+}
+
+// RuleFormula is one editable formula of the custom house rules
+type RuleFormula struct {
+	Group   string // "computed", "parameters", "skills" or "damageBonus"
+	Key     string
+	Formula string
+}
+
+// StatusRules describes the character's house rules
+type StatusRules struct {
+	Profile  string
+	Options  []Option
+	Custom   bool          // formulas are editable
+	Formulas []RuleFormula // formulas in effect, in display order
+}
+
+// StatusEra describes the character's era preset
+type StatusEra struct {
+	Era     string
+	Options []Option
+}
+
+// StatusGeneration describes the character's stat generation mode
+type StatusGeneration struct {
+	Mode    string
+	Options []Option
+	CanRoll bool  // ランダム and per-stat rerolls are available
+	Budget  int   // point-buy budget (0 unless point-buy)
+	Used    int   // points spent in point-buy
+	Array   []int // fixed values (nil unless array mode)
+}
+
+// Remaining returns unspent point-buy points
+func (g StatusGeneration) Remaining() int {
+	return g.Budget - g.Used
+}
+
+// SanCheck represents one entry of the SAN check history
+type SanCheck struct {
+	Expr       string
+	SanBefore  int
+	SanAfter   int
+	Roll       int
+	Success    bool
+	LossDetail string
+	Loss       int
+	Temporary  bool
+	Indefinite bool
+}
+
+// Insanity represents a recorded insanity, phobia or mania
+type Insanity struct {
+	Label     string
+	Kind      string
+	KindLabel string
+}
+
+// StatusSanity holds SAN loss tracking and insanity state
+type StatusSanity struct {
+	HasSession   bool // a session is being tracked
+	SessionStart int  // SAN at the first check of the session
+	SessionLoss  int
+	Temporary    bool
+	Indefinite   bool
+	Insanities   []Insanity
+	Kinds        []Option
+	Checks       []SanCheck // newest first
+}
+
 // StatusState holds all status-related data for rendering
 type StatusState struct {
 	Variables   []StatusVariable
 	Computed    []ComputedValue
 	Parameters  []StatusParameter
 	DamageBonus string
+	Rolls       []StatRoll // roll log, newest first
+	Generation  StatusGeneration
+	Rules       StatusRules
+	Era         StatusEra
+	Sanity      StatusSanity
 }
 
 // SkillCategory represents a group of skills
@@ -183,9 +270,121 @@ type SkillsState struct {
 	Remaining    SkillPoints
 }
 
+// CheckRoll represents one entry of the skill check history
+type CheckRoll struct {
+	Label      string // e.g. "目星", "STR×5"
+	Target     int    // value plus modifier
+	Modifier   int
+	Roll       int
+	Level      string // critical, special, success, failure, fumble
+	LevelLabel string // e.g. "スペシャル"
+}
+
+// GrowthResult represents one skill's growth check
+type GrowthResult struct {
+	Label   string
+	Before  int
+	Roll    int
+	Success bool
+	Gain    int
+	After   int
+}
+
+// GrowthReport represents one growth resolution for keeper review
+type GrowthReport struct {
+	Time    string // formatted resolution time
+	Gained  int    // number of skills that grew
+	Results []GrowthResult
+}
+
+// OccupationPick is one option of a "choose N" slot
+type OccupationPick struct {
+	Value    string // "<slot>:<option>", posted back as a "choice" form value
+	Label    string // e.g. "拳銃", "芸術(任意)"
+	Selected bool
+}
+
+// OccupationChoice is a "choose Count" slot of an occupation
+type OccupationChoice struct {
+	Count int
+	Picks []OccupationPick
+}
+
+// OccupationState holds the selected occupation and the catalog for the picker
+type OccupationState struct {
+	ID        string // empty when no occupation is selected
+	Name      string
+	Options   []Option // catalog entries
+	CreditMin int
+	CreditMax int
+	Skills    []string // fixed occupation skill labels
+	Choices   []OccupationChoice
+}
+
+// Weapon represents one row of the combat table
+type Weapon struct {
+	Name        string
+	Skill       string
+	SkillValue  int
+	HasSkill    bool   // linked skill exists on the sheet
+	Damage      string // as entered, e.g. "1D3"
+	DamageExpr  string // rolled expression, e.g. "1D3+1d4" for melee
+	Range       string
+	Attacks     string
+	Ammo        int
+	Malfunction int
+	HP          int
+	Melee       bool
+}
+
+// DamageRoll represents one entry of the damage roll history
+type DamageRoll struct {
+	Weapon string
+	Detail string // e.g. "1D3[2]+1D4[3]"
+	Total  int
+}
+
+// WeaponsState holds the combat table and the catalog for adding weapons
+type WeaponsState struct {
+	Weapons []Weapon
+	Catalog []Option
+	Damage  []DamageRoll // newest first
+}
+
+// Item represents one inventory entry
+type Item struct {
+	Name     string
+	Quantity int
+	Notes    string
+	Stored   bool
+}
+
+// InventoryState holds the inventory and finances
+type InventoryState struct {
+	Items         []Item
+	Cash          int
+	Assets        int
+	Income        int
+	IncomeDerived bool // Income comes from 信用 rather than an override
+	Credit        int  // 信用 the derived income is based on
+}
+
+// Warning represents one rule violation shown in the warnings panel
+type Warning struct {
+	Kind    string // e.g. "job-overspent", "skill-over-cap"
+	Subject string // skill label or variable key; empty for sheet-wide warnings
+	Message string
+}
+
 // SheetState holds all data needed to render a character sheet
 type SheetState struct {
-	PC     PageContext
-	Status StatusState
-	Skills SkillsState
+	PC         PageContext
+	Status     StatusState
+	Skills     SkillsState
+	Occupation OccupationState
+	Weapons    WeaponsState
+	Inventory  InventoryState
+	Warnings   []Warning
+	Checks     []CheckRoll    // check history, newest first
+	Growth     []GrowthReport // growth reports, newest first
 }